@@ -0,0 +1,99 @@
+package cli
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// builtinCommands are the commands App provides on its own.
+var builtinCommands = []string{"help", "version", "shell", "run-script"}
+
+// aliases returns the aliases defined in the [alias] section of the
+// configuration file, loading them on the first call.
+//
+// Aliases shadowing commands, either built-in or not, are ignored
+// with a warning.
+func (a *App) aliases() (map[string]string, error) {
+	if a.aliasTable != nil {
+		return a.aliasTable, nil
+	}
+
+	cfg, err := loadConfig(a.configFile())
+	if err != nil {
+		return nil, err
+	}
+
+	a.aliasTable = make(map[string]string)
+	for _, name := range aliasNames(cfg["alias"]) {
+		value := cfg["alias"][name]
+		if err := validateName("alias", name, maxNameLength, "_-"); err != nil {
+			a.printwarn(err)
+			continue
+		}
+		if a.isCommand(name) {
+			a.printwarn(fmt.Sprintf(`alias %q ignored, it would shadow a command`, name))
+			continue
+		}
+		a.aliasTable[name] = value
+	}
+	return a.aliasTable, nil
+}
+
+// isCommand reports whether name is a command, either built-in or not.
+func (a *App) isCommand(name string) bool {
+	for _, builtin := range builtinCommands {
+		if name == builtin {
+			return true
+		}
+	}
+	return a.commandByName(name) != nil
+}
+
+// expandAliases replaces the alias the arguments start with by its
+// value, until the first argument is not an alias.
+func (a *App) expandAliases(arguments []string) ([]string, error) {
+	if len(arguments) == 0 {
+		return arguments, nil
+	}
+
+	aliases, err := a.aliases()
+	if err != nil {
+		return nil, err
+	}
+
+	var seen []string
+	for {
+		name := arguments[0]
+		value, ok := aliases[name]
+		if !ok {
+			return arguments, nil
+		}
+
+		for _, each := range seen {
+			if each == name {
+				return nil, fmt.Errorf(`alias loop: %s -> %s`, strings.Join(seen, " -> "), name)
+			}
+		}
+		seen = append(seen, name)
+
+		expansion, err := Split(value)
+		if err != nil {
+			return nil, fmt.Errorf(`alias %q: %s`, name, err)
+		}
+		if len(expansion) == 0 {
+			return nil, fmt.Errorf(`alias %q is empty`, name)
+		}
+		arguments = append(expansion, arguments[1:]...)
+	}
+}
+
+// aliasNames returns sorted names of the aliases.
+func aliasNames(aliases map[string]string) []string {
+	var names []string
+	for name := range aliases {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
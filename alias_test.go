@@ -0,0 +1,123 @@
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func aliasApp(t *testing.T, config string, opened *[]string) *App {
+	a := NewApp("cli")
+	a.ConfigFile = filepath.Join(t.TempDir(), "config")
+	if err := os.WriteFile(a.ConfigFile, []byte(config), 0644); err != nil {
+		t.Fatal(err)
+	}
+	a.AddCommand(&Command{
+		Name:  "open",
+		Brief: "opens smth",
+		Flags: []*Flag{{Name: "all", Short: "a"}},
+		Handle: func(args *Args) int {
+			*opened = append(*opened, args.String("all"))
+			return 0
+		},
+	})
+	return a
+}
+
+func TestRun_Alias(t *testing.T) {
+	var opened []string
+	a := aliasApp(t, `
+# aliases
+[alias]
+	o = open
+	oa = o --all 'every thing'
+	open = open --all never
+	help = version
+	loop = pool
+	pool = loop
+`, &opened)
+	defer setArguments()
+	defer output.Reset()
+
+	setArguments("oa")
+	if exitcode := a.Run(); exitcode != 0 {
+		t.Errorf("finished with code %d, expected 0", exitcode)
+	}
+	if !reflect.DeepEqual(opened, []string{"every thing"}) {
+		t.Errorf("unexpected commands executed: %v", opened)
+	}
+	expected := "cli: warning: alias \"help\" ignored, it would shadow a command\n" +
+		"cli: warning: alias \"open\" ignored, it would shadow a command\n"
+	if output.String() != expected {
+		t.Errorf("unexpected warnings: %q", output.String())
+	}
+
+	if exitcode, _ := a.dispatch([]string{"loop"}); exitcode != 1 {
+		t.Errorf("alias loop finished with code %d, expected 1", exitcode)
+	}
+	if !strings.Contains(output.String(), "alias loop: loop -> pool -> loop\n") {
+		t.Errorf("alias loop isn't reported: %q", output.String())
+	}
+
+	output.Reset()
+	a.dispatch([]string{"help"})
+	expected = "The aliases are:\n\n" +
+		"\tloop        pool\n" +
+		"\to           open\n" +
+		"\toa          o --all 'every thing'\n" +
+		"\tpool        loop\n"
+	if !strings.Contains(output.String(), expected) {
+		t.Errorf("aliases aren't listed in help: %q", output.String())
+	}
+
+	output.Reset()
+	a.dispatch([]string{"help", "o"})
+	if output.String() != "o is an alias for open\n" {
+		t.Errorf("unexpected alias help: %q", output.String())
+	}
+}
+
+func TestRun_AliasBrokenConfig(t *testing.T) {
+	var opened []string
+	a := aliasApp(t, "key = value\n", &opened)
+	defer output.Reset()
+
+	if exitcode, _ := a.dispatch([]string{"open"}); exitcode != 1 {
+		t.Errorf("broken config finished with code %d, expected 1", exitcode)
+	}
+	if len(opened) != 0 || !strings.Contains(output.String(), "cli: error: ") {
+		t.Errorf("broken config isn't reported: %q", output.String())
+	}
+
+	for _, command := range []string{"help", "version"} {
+		a := aliasApp(t, "key = value\n", &opened)
+		a.Version = "1.0"
+		output.Reset()
+		if exitcode, _ := a.dispatch([]string{command}); exitcode != 0 {
+			t.Errorf("%s with a broken config finished with code %d, expected 0", command, exitcode)
+		}
+		if !strings.HasPrefix(output.String(), "cli: warning: ") {
+			t.Errorf("%s doesn't warn about the broken config: %q", command, output.String())
+		}
+	}
+}
+
+func TestLoadConfig(t *testing.T) {
+	if cfg, err := loadConfig(filepath.Join(t.TempDir(), "nothing")); err != nil || len(cfg) != 0 {
+		t.Errorf("missing config loaded as %v, %v", cfg, err)
+	}
+
+	path := filepath.Join(t.TempDir(), "config")
+	os.WriteFile(path, []byte("key = value\n"), 0644)
+	if _, err := loadConfig(path); err == nil {
+		t.Error("key outside of a section is accepted")
+	}
+
+	os.WriteFile(path, []byte("[Alias]\n; comment\nCO = checkout -b\n"), 0644)
+	cfg, err := loadConfig(path)
+	if err != nil || cfg["alias"]["co"] != "checkout -b" {
+		t.Errorf("unexpected config: %v, %v", cfg, err)
+	}
+}
@@ -5,9 +5,11 @@ import (
 	"io"
 	"os"
 	"strings"
+	"unicode/utf8"
 )
 
 var (
+	Stdin  io.Reader = os.Stdin
 	Stdout io.Writer = os.Stdout
 	Stderr io.Writer = os.Stderr
 )
@@ -17,13 +19,80 @@ var (
 // By default, Cli provides its own implementation of version
 // command, but it will use "version" command instead if you
 // provide one.
+//
+// Version is usually copied from a variable of package main, since
+// -ldflags="-X ..." sets variables, not struct fields:
+//
+//	var version = "devel" // -ldflags="-X main.version=1.5"
+//
+//	app.Version = version
 type App struct {
 	Name    string // `go`
 	Brief   string // `Go is a tool for managing Go source code.`
-	Version string // `1.5`
+	Version string // `1.5`, usually copied from main.version
 	Strict  bool   // default is false
+	Color   string // `auto`, `always` or `never`, default is auto
+	Theme   *Theme // styling of help and errors, DefaultTheme if nil
+	Pager   bool   // page long help through $PAGER, NewApp turns it on
+
+	// BuildInfo turns on build details in the output of the version
+	// command: the module, VCS revision and commit time, and the Go
+	// version.
+	// If Version is empty, the version of the main module is used.
+	BuildInfo bool
+
+	// LogFormat is the format of Args.Logger records, `text` or
+	// `json`, default is text.
+	LogFormat string
+
+	// Suggester suggests names for mistyped ones, DefaultSuggester if nil.
+	Suggester Suggester
+
+	// Plugins turns on external commands. Unknown subcommands are
+	// looked up as `name-subcommand` executables in PluginDir and
+	// then in PATH, like git does.
+	Plugins   bool
+	PluginDir string
+
+	// Shell turns on the `shell` command, which runs commands typed
+	// interactively. History is kept in HistoryFile, which defaults
+	// to ~/.name_history.
+	Shell       bool
+	HistoryFile string
+
+	// Scripts turns on the `run-script` command, which executes
+	// commands from a file, one per line.
+	Scripts bool
+
+	// ConfigFile is the git-style configuration file users define
+	// aliases in, ~/.config/name/config by default:
+	//
+	//	[alias]
+	//		co = checkout -b
+	ConfigFile string
+
+	aliasTable map[string]string
+
+	// Flags are global options, accepted before or after the
+	// subcommand name by every command. Before the name, values
+	// are joined with an equals sign or given as the next argument,
+	// --config=file or --config file, unless the flag is a switch.
+	//
+	// Flags of commands take precedence over global ones with the
+	// same names. Besides these, --color and --no-pager are always
+	// accepted.
+	Flags []*Flag
+
+	// Root handles invocations without a subcommand: bare ones,
+	// ones starting with a flag and ones with arguments none of
+	// the commands is named after, e.g. `name file.txt`. It accepts
+	// positional arguments in strict mode.
+	//
+	// Only arguments of 3 or more characters a single typo away from
+	// a command name, e.g. `name opne` for open, are taken for
+	// mistyped commands. Prefixes of command names go to Root.
+	Root *Command
 
-	Root     *Command
 	Commands []*Command
 	Topics   []*Topic
 }
@@ -35,7 +104,7 @@ func NewApp(name string) *App {
 		panic("can't construct an app without a name")
 	}
 
-	return &App{Name: name, Strict: true}
+	return &App{Name: name, Strict: true, Pager: true}
 }
 
 func (a *App) println(stuff ...interface{}) {
@@ -47,8 +116,15 @@ func (a *App) printf(format string, stuff ...interface{}) {
 }
 
 func (a *App) printerr(err ...interface{}) {
+	prefix := paint(a.theme(Stderr).Error, "error:")
 	for _, each := range err {
-		fmt.Fprintln(Stderr, a.Name+":", each)
+		fmt.Fprintln(Stderr, a.Name+":", prefix, each)
+	}
+}
+
+func (a *App) printwarn(warning ...interface{}) {
+	for _, each := range warning {
+		fmt.Fprintln(Stderr, a.Name+": warning:", each)
 	}
 }
 
@@ -62,40 +138,84 @@ func (a *App) commandByName(name string) *Command {
 	return nil
 }
 
-func (a *App) topicByName(name string) *Topic {
-	for i, topic := range a.Topics {
-		if topic.Name == name {
-			return a.Topics[i]
-		}
-	}
-
-	return nil
-}
-
 // AddCommand does literally what its name says.
-func (a *App) AddCommand(command *Command) {
+//
+// It refuses commands violating the naming rules documented
+// on Command and Flag.
+func (a *App) AddCommand(command *Command) error {
+	if err := validateCommand(command); err != nil {
+		return err
+	}
 	a.Commands = append(a.Commands, command)
+	return nil
 }
 
 // AddTopic does literally what its name says.
-func (a *App) AddTopic(topic *Topic) {
+//
+// It refuses topics violating the naming rules documented on Topic.
+func (a *App) AddTopic(topic *Topic) error {
+	if err := validateTopic(topic); err != nil {
+		return err
+	}
 	a.Topics = append(a.Topics, topic)
+	return nil
 }
 
 // SuggestionsFor provides suggestions for the typedName.
 func (a *App) SuggestionsFor(typedName string) []string {
-	minimumDistance := 2
-	suggestions := []string{}
+	var names []string
+	for _, cmd := range a.Commands {
+		names = append(names, cmd.Name)
+	}
+	return a.suggest(typedName, names)
+}
+
+// helpSuggestionsFor provides command and topic suggestions for
+// the typedName.
+func (a *App) helpSuggestionsFor(typedName string) []string {
+	var names []string
 	for _, cmd := range a.Commands {
-		ld := levenshteinDistance(typedName, cmd.Name, true)
-		hasPrefix := strings.HasPrefix(strings.ToLower(cmd.Name), strings.ToLower(typedName))
-		if ld <= minimumDistance || hasPrefix {
-			suggestions = append(suggestions, cmd.Name)
+		names = append(names, cmd.Name)
+	}
+	walkTopics("", a.Topics, func(path string, topic *Topic) {
+		names = append(names, path)
+	})
+	return a.suggest(typedName, names)
+}
+
+// flagSuggestionsFor provides suggestions for the unknown option
+// name, both long and short ones.
+func (a *App) flagSuggestionsFor(name string, flags []*Flag) []string {
+	var names []string
+	options := make(map[string]string)
+	for _, flag := range flags {
+		for _, option := range [][2]string{{"--", flag.Name}, {"-", flag.Short}} {
+			if _, ok := options[option[1]]; option[1] != "" && !ok {
+				names = append(names, option[1])
+				options[option[1]] = option[0] + option[1]
+			}
 		}
 	}
+
+	var suggestions []string
+	for _, s := range a.suggest(name, names) {
+		suggestions = append(suggestions, options[s])
+	}
 	return suggestions
 }
 
+// printSuggestions prints "Did you mean this?" followed by the list.
+func (a *App) printSuggestions(suggestions []string) {
+	if len(suggestions) == 0 {
+		return
+	}
+	a.println("Did you mean this?")
+	for _, s := range suggestions {
+		a.printf("\t%v\n", s)
+	}
+	a.println("")
+}
+
 // Run executes a a.
 //
 // Take a note, Run panics if len(os.Args) < 1
@@ -103,18 +223,67 @@ func (a *App) Run() int {
 	if len(os.Args) < 1 {
 		panic("shell-provided arguments are not present")
 	}
-	arguments := os.Args[1:]
+
+	if err := a.Validate(); err != nil {
+		a.printerr(err)
+		os.Exit(1)
+	}
+
+	exitCode, fatal := a.dispatch(os.Args[1:])
+	if fatal {
+		os.Exit(exitCode)
+	}
+	return exitCode
+}
+
+// Exec executes a single command line, as if it was given to Run,
+// e.g. a line typed in the shell.
+//
+// Unlike Run, it never exits the process, usage errors result in
+// exit code 1.
+func (a *App) Exec(arguments []string) (exitCode int) {
+	exitCode, _ = a.dispatch(arguments)
+	return exitCode
+}
+
+// dispatch runs whatever the arguments call for. Fatal is true for
+// usage errors, which Run exits the process on.
+func (a *App) dispatch(arguments []string) (exitCode int, fatal bool) {
+	// $ program --color=always --no-pager ...
+	//           ^ global flags
+	globals, arguments, err := a.parseGlobals(arguments)
+	if err != nil {
+		a.printerr(err)
+		return 1, true
+	}
+
+	// $ program help
+	//           ^ works even if the configuration file is broken
+	if len(arguments) > 0 && (arguments[0] == "help" || arguments[0] == "version") {
+		if _, err := a.aliases(); err != nil {
+			a.printwarn(err)
+			a.aliasTable = make(map[string]string)
+		}
+	}
+
+	// $ program alias ...
+	//           ^ replaced by its value
+	arguments, err = a.expandAliases(arguments)
+	if err != nil {
+		a.printerr(err)
+		return 1, true
+	}
 
 	// $ program
 	// $ program -flag
 	//           ^ no subcommand
-	if len(arguments) == 0 || ((len(arguments) > 0) && strings.HasPrefix(arguments[0], "-")) {
+	if len(arguments) == 0 || strings.HasPrefix(arguments[0], "-") {
 		if a.Root != nil {
-			return a.Root.Run(a)
+			return a.runRoot(globals, arguments)
 		}
 
-		a.println(a.globalHelp())
-		return 0
+		a.page(a.globalHelp())
+		return 0, false
 	}
 
 	subcommandName := arguments[0]
@@ -124,52 +293,111 @@ func (a *App) Run() int {
 		// $ program help
 		//           ^ one argument
 		if len(arguments) <= 1 {
-			if a.Root != nil {
-				a.println(a.commandHelp(a.Root))
-			} else {
-				a.println(a.globalHelp())
+			a.page(a.globalHelp())
+			return 0, false
+		}
+
+		// $ program help -k keyword
+		if arguments[1] == "-k" || arguments[1] == "--keyword" {
+			keyword := strings.Join(arguments[2:], " ")
+			if strings.TrimSpace(keyword) == "" {
+				a.printerr("no keyword to search for")
+				return 1, true
+			}
+
+			results := a.search(keyword)
+			if len(results) == 0 {
+				a.printerr("nothing matches \"" + keyword + "\"")
+				return 1, true
 			}
-			return 0
+			a.page(a.searchHelp(keyword, results))
+			return 0, false
 		}
 
 		command := a.commandByName(arguments[1])
 		if command != nil {
-			a.println(a.commandHelp(command))
-			return 0
+			a.page(a.commandHelp(command))
+			return 0, false
 		}
 
-		topic := a.topicByName(arguments[1])
+		topic := a.topicByPath(arguments[1:]...)
 		if topic != nil {
-			a.println(topic.Text)
-			return 0
+			a.page(a.topicHelp(topic))
+			return 0, false
+		}
+
+		// $ program help alias
+		if aliases, _ := a.aliases(); aliases[arguments[1]] != "" {
+			a.printf("%s is an alias for %s\n", arguments[1], aliases[arguments[1]])
+			return 0, false
+		}
+
+		// $ program help plugin
+		//           ^ same as `program plugin --help`
+		if path, ok := a.lookPlugin(arguments[1]); ok {
+			return a.runPlugin(path, []string{"--help"}), false
 		}
 
 		a.printerr("no such command or help topic")
-		os.Exit(1)
+		a.printSuggestions(a.helpSuggestionsFor(strings.Join(arguments[1:], "/")))
+		return 1, true
 	}
 
 	if subcommandName == "version" {
 		if subcommand != nil {
-			return subcommand.Run(a)
+			return subcommand.run(a, globals, arguments[1:])
 		}
 
-		a.printf("%s version %s\n", a.Name, a.Version)
-		return 0
+		return a.version(arguments[1:])
+	}
+
+	if subcommandName == "shell" && subcommand == nil && a.Shell {
+		return a.shell(), false
+	}
+
+	if subcommandName == "run-script" && subcommand == nil && a.Scripts {
+		return a.runScript(arguments[1:])
 	}
 
 	if subcommand != nil {
-		return subcommand.Run(a)
+		return subcommand.run(a, globals, arguments[1:])
+	}
+
+	if path, ok := a.lookPlugin(subcommandName); ok {
+		return a.runPlugin(path, arguments[1:]), false
+	}
+
+	// $ program file.txt
+	//           ^ not a subcommand, an argument of the root command,
+	//             unless it's a mistyped command name
+	if a.Root != nil && !a.mistyped(subcommandName) {
+		return a.runRoot(globals, arguments)
 	}
 
 	a.printerr("unknown subcommand \"" + subcommandName + "\"\n")
-	if suggestions := a.SuggestionsFor(subcommandName); len(suggestions) > 0 {
-		a.println("Did you mean this?")
-		for _, s := range suggestions {
-			a.printf("\t%v\n", s)
+	a.printSuggestions(a.SuggestionsFor(subcommandName))
+	return 1, true
+}
+
+// runRoot runs the root command, which accepts positional arguments
+// even in strict mode.
+func (a *App) runRoot(globals *Args, arguments []string) (exitCode int, fatal bool) {
+	root := *a.Root
+	root.Positional = true
+	return root.run(a, globals, arguments)
+}
+
+// mistyped reports whether the name is taken for a mistyped command
+// rather than an argument of the root command: it's at least 3
+// characters long and a single typo away from a command name.
+func (a *App) mistyped(name string) bool {
+	if utf8.RuneCountInString(name) < 3 {
+		return false
+	}
+	for _, cmd := range a.Commands {
+		if DamerauLevenshtein(name, cmd.Name) <= 1 {
+			return true
 		}
-		a.println("")
 	}
-	os.Exit(1)
-
-	return 1
+	return false
 }
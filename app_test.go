@@ -3,6 +3,8 @@ package cli
 import (
 	"bytes"
 	"os"
+	"reflect"
+	"strings"
 	"testing"
 )
 
@@ -11,6 +13,12 @@ var output bytes.Buffer
 func init() {
 	Stdout = &output
 	Stderr = &output
+
+	// Help is wrapped to the terminal width.
+	os.Setenv("COLUMNS", "80")
+
+	// Aliases are looked up in the user configuration.
+	os.Setenv("XDG_CONFIG_HOME", "/nonexistent")
 }
 
 func setArguments(args ...string) {
@@ -62,6 +70,13 @@ The commands are:
 
 Use "cli help [command]" for more information about a command.
 
+The global options are:
+
+	--color[=WHEN]
+		Colorize the output: auto, always or never.
+	--no-pager
+		Don't page long help through $PAGER.
+
 Additional help topics:
 
 	writing     how to write
@@ -152,26 +167,56 @@ func TestRun_Help(t *testing.T) {
 
 func TestAddCommand(t *testing.T) {
 	a := App{}
-	a.AddCommand(&Command{})
+	if err := a.AddCommand(&Command{Name: "open"}); err != nil {
+		t.Error(err)
+	}
 	if len(a.Commands) != 1 {
 		t.Error("broken")
 	}
+
+	for _, invalid := range []*Command{
+		nil,
+		{},
+		{Name: "-open"},
+		{Name: "open door"},
+		{Name: "openthedoors"},
+		{Name: "open", Flags: []*Flag{{Name: "all", Short: "abcd"}}},
+	} {
+		if err := a.AddCommand(invalid); err == nil {
+			t.Errorf("invalid command %v was added", invalid)
+		}
+	}
+	if len(a.Commands) != 1 {
+		t.Error("invalid commands got added")
+	}
 }
 
 func TestAddTopic(t *testing.T) {
 	a := App{}
-	a.AddTopic(&Topic{})
+	if err := a.AddTopic(&Topic{Name: "writing"}); err != nil {
+		t.Error(err)
+	}
 	if len(a.Topics) != 1 {
 		t.Error("broken")
 	}
+
+	if err := a.AddTopic(&Topic{Name: "how_to_write"}); err == nil {
+		t.Error("topic with a long name was added")
+	}
 }
 
 func TestAddFlag(t *testing.T) {
 	var c Command
-	c.AddFlag(&Flag{})
+	if err := c.AddFlag(&Flag{Name: "dry-run", Short: "n"}); err != nil {
+		t.Error(err)
+	}
 	if len(c.Flags) != 1 {
 		t.Error("broken")
 	}
+
+	if err := c.AddFlag(&Flag{Name: "dry", Short: "-n"}); err == nil {
+		t.Error("flag with an invalid short name was added")
+	}
 }
 
 func TestAddExample(t *testing.T) {
@@ -181,3 +226,138 @@ func TestAddExample(t *testing.T) {
 		t.Error("failed to add example")
 	}
 }
+
+func TestRun_NoPager(t *testing.T) {
+	a := NewApp("cli")
+	a.Brief = "cli is a thing"
+	setArguments("--no-pager", "help")
+	defer setArguments()
+	defer output.Reset()
+
+	if exitcode := a.Run(); exitcode != 0 {
+		t.Errorf("finished with code %d, expected 0", exitcode)
+	}
+	if a.Pager {
+		t.Error("--no-pager didn't turn the pager off")
+	}
+	if !strings.HasPrefix(output.String(), "cli is a thing\n") {
+		t.Errorf("unexpected output: %q", output.String())
+	}
+}
+
+func TestSuggestionsFor(t *testing.T) {
+	a := NewApp("cli")
+	a.AddCommand(&Command{Name: "open"})
+	a.AddCommand(&Command{Name: "close"})
+	a.AddCommand(&Command{Name: "ls"})
+	a.AddTopic(&Topic{Name: "writing", Topics: []*Topic{{Name: "poems"}}})
+
+	if s := a.SuggestionsFor("opne"); !reflect.DeepEqual(s, []string{"open"}) {
+		t.Errorf("unexpected suggestions: %v", s)
+	}
+	if s := a.SuggestionsFor("x"); len(s) != 0 {
+		t.Errorf("unexpected suggestions: %v", s)
+	}
+	if s := a.helpSuggestionsFor("writing/poem"); !reflect.DeepEqual(s, []string{"writing/poems"}) {
+		t.Errorf("unexpected suggestions: %v", s)
+	}
+}
+
+func TestRun_Root(t *testing.T) {
+	var ran string
+	var got []string
+	handle := func(name string) CmdHandler {
+		return func(args *Args) int {
+			ran, got = name, args.Arguments()
+			return 0
+		}
+	}
+
+	a := NewApp("demo")
+	a.Root = &Command{
+		Usage:  "[-f] [files]",
+		Help:   "Demo prints files.",
+		Flags:  []*Flag{{Name: "force", Short: "f", Help: "Print anyway."}},
+		Handle: handle("root"),
+	}
+	a.Commands = []*Command{
+		{Name: "open", Brief: "opens smth", Positional: true, Handle: handle("open")},
+		{Name: "ls", Brief: "lists smth", Handle: handle("ls")},
+	}
+	defer setArguments()
+	defer output.Reset()
+
+	check := func(arguments []string, name string, expected ...string) {
+		ran, got = "", nil
+		if exitcode, _ := a.dispatch(arguments); exitcode != 0 {
+			t.Errorf("%v finished with code %d", arguments, exitcode)
+		}
+		if ran != name || !reflect.DeepEqual(got, expected) {
+			t.Errorf("%v ran %s with %v, expected %s with %v", arguments, ran, got, name, expected)
+		}
+	}
+
+	check(nil, "root")
+	check([]string{"-f"}, "root")
+	check([]string{"somefile.txt", "other.txt", "-f"}, "root", "somefile.txt", "other.txt")
+	check([]string{"open", "file.txt"}, "open", "file.txt")
+	check([]string{"o"}, "root", "o")
+	check([]string{"op", "-f"}, "root", "op")
+	check([]string{"1s"}, "root", "1s")
+
+	if exitcode, _ := a.dispatch([]string{"opne"}); exitcode != 1 {
+		t.Errorf("mistyped command finished with code %d, expected 1", exitcode)
+	}
+	if !strings.Contains(output.String(), "Did you mean this?\n\topen\n") {
+		t.Errorf("mistyped command isn't suggested: %q", output.String())
+	}
+	output.Reset()
+	if exitcode, _ := a.dispatch([]string{"ls", "stray", "args"}); exitcode != 1 {
+		t.Errorf("stray arguments finished with code %d, expected 1", exitcode)
+	}
+	if !strings.Contains(output.String(), "no option name before argument stray") {
+		t.Errorf("stray arguments aren't reported: %q", output.String())
+	}
+
+	setArguments("somefile.txt")
+	if a.Root.Run(a); ran != "root" || !reflect.DeepEqual(got, []string{"somefile.txt"}) {
+		t.Errorf("Command.Run skipped the argument: %v", got)
+	}
+	setArguments("--color=never", "open", "file.txt")
+	if a.Commands[0].Run(a); ran != "open" || !reflect.DeepEqual(got, []string{"file.txt"}) {
+		t.Errorf("Command.Run didn't skip the subcommand: %v", got)
+	}
+
+	expected := `Usage:
+
+	demo [-f] [files]
+	demo command [arguments]
+
+Demo prints files.
+
+Available options:
+
+	-f, --force=""
+		Print anyway.
+
+The commands are:
+
+	open        opens smth
+	ls          lists smth
+
+Use "demo help [command]" for more information about a command.
+
+The global options are:
+
+	--color[=WHEN]
+		Colorize the output: auto, always or never.
+	--no-pager
+		Don't page long help through $PAGER.
+
+`
+	output.Reset()
+	a.Exec([]string{"help"})
+	if output.String() != expected {
+		t.Errorf("unexpected help:\n%q\n%q", output.String(), expected)
+	}
+}
@@ -1,24 +1,41 @@
 package cli
 
 import (
+	"context"
+	"log/slog"
 	"strconv"
+
+	"github.com/ccpaging/cli/progress"
+	"github.com/ccpaging/cli/prompt"
 )
 
 // Args is a set of arguments and options of command call.
 type Args struct {
-	app  *App
-	vars map[string]string
+	app       *App
+	vars      map[string]string
+	counts    map[string]int
+	arguments []string
+	prompter  *prompt.Prompter
+	ctx       context.Context
+	cancel    context.CancelFunc
+	display   *progress.Display
+	logger    *slog.Logger
 }
 
-func newContext(a *App, flags []*Flag, argv []string) (*Args, error) {
-	vars, err := parseVariables(a.Strict, flags, argv)
+func newContext(a *App, flags []*Flag, argv []string, positional bool) (*Args, error) {
+	p, err := parseArguments(a.Strict, positional, flags, argv)
+	if unknown, ok := err.(*unknownOptionError); ok {
+		unknown.suggestions = a.flagSuggestionsFor(unknown.name, flags)
+	}
 	if err != nil {
 		return nil, err
 	}
 
 	c := &Args{
-		app:  a,
-		vars: vars,
+		app:       a,
+		vars:      p.vars,
+		counts:    p.counts,
+		arguments: p.arguments,
 	}
 	return c, nil
 }
@@ -42,6 +59,12 @@ func (c *Args) Has(flagName string) bool {
 	return ok
 }
 
+// Count returns the number of times the flag is given, e.g. 3 for
+// `-v -vv`. Boolean flags set to false, e.g. `-v=false`, don't count.
+func (c *Args) Count(flagName string) int {
+	return c.counts[flagName]
+}
+
 // String returns a string of corresponding variable flag.
 // Second (bool) parameter says whether it's really defined or not.
 func (c *Args) String(flagName string) string {
@@ -100,3 +123,11 @@ func (c *Args) Int(flagName string) int {
 func (c *Args) Variables() map[string]string {
 	return c.vars
 }
+
+// Arguments returns the positional arguments, e.g. files given to
+// the root command: those preceding the options and those following
+// boolean ones. Arguments following other options are their value.
+// In strict mode, only the root command and Positional ones have any.
+func (c *Args) Arguments() []string {
+	return c.arguments
+}
@@ -0,0 +1,239 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// binding is a struct field bound to a flag.
+type binding struct {
+	flag  *Flag
+	env   string
+	field []int
+}
+
+var (
+	durationType = reflect.TypeOf(time.Duration(0))
+	valueType    = reflect.TypeOf((*Value)(nil)).Elem()
+)
+
+// bindings parses the `cli` tags of the struct v points to:
+//
+//	Output string `cli:"output,short=o,default=.,env=OUTPUT" help:"Write files to the directory."`
+//	Token  string `cli:"token,env=TOKEN,required"`
+//
+// The name defaults to the lowercased field name. Fields without
+// the tag are left alone. Required flags are asked for, see
+// Flag.Required, unless their environment variable is set. Commas in
+// option values are escaped with a backslash, doubled in the tag
+// literal: `cli:"words,default=a\\,b"`.
+func bindings(v interface{}) ([]binding, error) {
+	ptr := reflect.ValueOf(v)
+	if ptr.Kind() != reflect.Ptr || ptr.Elem().Kind() != reflect.Struct {
+		return nil, fmt.Errorf(`can't bind flags to %T, expected a pointer to a struct`, v)
+	}
+
+	var result []binding
+	t := ptr.Elem().Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag, ok := field.Tag.Lookup("cli")
+		if !ok || tag == "-" || field.PkgPath != "" {
+			continue
+		}
+		if !bindable(field.Type) {
+			return nil, fmt.Errorf(`can't bind flags to field %s of type %s`, field.Name, field.Type)
+		}
+
+		b := binding{
+			flag:  &Flag{Help: field.Tag.Get("help")},
+			field: field.Index,
+		}
+		if field.Type.Kind() == reflect.Bool {
+			b.flag.Value = switchValue{}
+		}
+		parts := splitTag(tag)
+		b.flag.Name = strings.TrimSpace(parts[0])
+		if b.flag.Name == "" {
+			b.flag.Name = strings.ToLower(field.Name)
+		}
+		for _, option := range parts[1:] {
+			key, value, _ := strings.Cut(option, "=")
+			switch strings.TrimSpace(key) {
+			case "short":
+				b.flag.Short = value
+			case "default":
+				b.flag.DefValue = value
+			case "env":
+				b.env = value
+			case "required":
+				b.flag.Required = true
+			default:
+				return nil, fmt.Errorf(`unknown option %q in the tag of field %s`, key, field.Name)
+			}
+		}
+		result = append(result, b)
+	}
+	return result, nil
+}
+
+// fromEnv reports whether the environment variable of the flag bound
+// to a field is set.
+func fromEnv(bound []binding, flag *Flag) bool {
+	for _, b := range bound {
+		if b.flag.Name == flag.Name && b.env != "" {
+			_, ok := os.LookupEnv(b.env)
+			return ok
+		}
+	}
+	return false
+}
+
+// splitTag splits a tag by commas not escaped with a backslash.
+func splitTag(tag string) []string {
+	var parts []string
+	var part strings.Builder
+	for i := 0; i < len(tag); i++ {
+		switch {
+		case tag[i] == '\\' && i+1 < len(tag) && tag[i+1] == ',':
+			part.WriteByte(',')
+			i++
+		case tag[i] == ',':
+			parts = append(parts, part.String())
+			part.Reset()
+		default:
+			part.WriteByte(tag[i])
+		}
+	}
+	return append(parts, part.String())
+}
+
+// switchValue makes a flag boolean, so it never takes the arguments
+// following it. The value itself is only checked, Args keeps it.
+type switchValue struct{}
+
+func (switchValue) String() string { return "" }
+func (switchValue) Type() string   { return "bool" }
+
+func (switchValue) Set(value string) error {
+	_, err := parseBool(value)
+	return err
+}
+
+func bindable(t reflect.Type) bool {
+	if t == durationType || reflect.PtrTo(t).Implements(valueType) {
+		return true
+	}
+	switch t.Kind() {
+	case reflect.String, reflect.Bool, reflect.Float32, reflect.Float64,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return true
+	case reflect.Slice:
+		return t.Elem().Kind() != reflect.Slice && bindable(t.Elem())
+	}
+	return false
+}
+
+// populate sets the bound fields of the struct v points to. Values
+// of flags given take precedence over environment variables, which
+// take precedence over defaults. Fields without any of those are
+// reset to zero values, so nothing leaks from one run to another.
+func populate(v interface{}, bound []binding, args *Args) error {
+	s := reflect.ValueOf(v).Elem()
+	for _, b := range bound {
+		field := s.FieldByIndex(b.field)
+		field.Set(reflect.Zero(field.Type()))
+
+		value, ok := args.Get(b.flag.Name)
+		if !ok && b.env != "" {
+			value, ok = os.LookupEnv(b.env)
+		}
+		if !ok && b.flag.DefValue != "" {
+			value, ok = b.flag.DefValue, true
+		}
+		if !ok {
+			continue
+		}
+
+		if err := setField(field, value); err != nil {
+			return fmt.Errorf(`invalid value %q for option --%s: %s`, value, b.flag.Name, err)
+		}
+	}
+	return nil
+}
+
+func setField(field reflect.Value, value string) error {
+	if v, ok := field.Addr().Interface().(Value); ok {
+		if value == "" && v.Type() == "bool" {
+			value = "true"
+		}
+		return v.Set(value)
+	}
+	if field.Type() == durationType {
+		d, err := time.ParseDuration(value)
+		if err != nil {
+			return err
+		}
+		field.SetInt(int64(d))
+		return nil
+	}
+
+	switch field.Kind() {
+	case reflect.String:
+		field.SetString(value)
+	case reflect.Bool:
+		b, err := parseBool(value)
+		if err != nil {
+			return err
+		}
+		field.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(value, 0, field.Type().Bits())
+		if err != nil {
+			return err
+		}
+		field.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(value, 0, field.Type().Bits())
+		if err != nil {
+			return err
+		}
+		field.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(value, field.Type().Bits())
+		if err != nil {
+			return err
+		}
+		field.SetFloat(f)
+	case reflect.Slice:
+		items := strings.FieldsFunc(value, func(r rune) bool {
+			return r == ',' || unicode.IsSpace(r)
+		})
+		slice := reflect.MakeSlice(field.Type(), len(items), len(items))
+		for i, item := range items {
+			if err := setField(slice.Index(i), item); err != nil {
+				return err
+			}
+		}
+		field.Set(slice)
+	}
+	return nil
+}
+
+// parseBool is strconv.ParseBool, accepting an empty value and on/off
+// the way Args.Bool does.
+func parseBool(value string) (bool, error) {
+	switch strings.ToLower(value) {
+	case "", "on":
+		return true, nil
+	case "off":
+		return false, nil
+	}
+	return strconv.ParseBool(value)
+}
@@ -0,0 +1,133 @@
+package cli
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+type joinOptions struct {
+	Separator string        `cli:"separator,short=s,default=-" help:"Put it between strings."`
+	Upper     bool          `cli:",short=u"`
+	Repeat    int           `cli:"repeat,env=CLI_TEST_REPEAT"`
+	Ratio     float64       `cli:"ratio"`
+	Timeout   time.Duration `cli:"timeout,default=1s"`
+	Words     []string      `cli:"words"`
+	Sizes     []uint8       `cli:"sizes"`
+	Limit     byteSize      `cli:"limit,default=1k"`
+	Ignored   string
+	Skipped   string `cli:"-"`
+}
+
+func TestBind(t *testing.T) {
+	var options joinOptions
+	var result joinOptions
+	a := NewApp("cli")
+	err := a.AddCommand(&Command{
+		Name: "join",
+		Bind: &options,
+		Handle: func(args *Args) int {
+			result = options
+			return 0
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cmd := a.Commands[0]
+	var names []string
+	for _, flag := range cmd.allFlags() {
+		names = append(names, flag.Name)
+	}
+	if strings.Join(names, " ") != "separator upper repeat ratio timeout words sizes limit" {
+		t.Errorf("unexpected flags: %v", names)
+	}
+
+	t.Setenv("CLI_TEST_REPEAT", "0x10")
+	check := func(arguments []string, expected joinOptions) {
+		if exitcode, _ := a.dispatch(append([]string{"join"}, arguments...)); exitcode != 0 {
+			t.Errorf("%v finished with code %d", arguments, exitcode)
+		}
+		if !reflect.DeepEqual(result, expected) {
+			t.Errorf("%v populated %+v, expected %+v", arguments, result, expected)
+		}
+	}
+
+	check(nil, joinOptions{Separator: "-", Repeat: 16, Timeout: time.Second, Limit: 1 << 10})
+	check([]string{"-s", ".", "-u", "--repeat=2", "--ratio", "0.5", "--timeout=1m", "--words", "a,b", "c", "--sizes=1,2", "--limit=2M"}, joinOptions{
+		Separator: ".",
+		Upper:     true,
+		Repeat:    2,
+		Ratio:     0.5,
+		Timeout:   time.Minute,
+		Words:     []string{"a", "b", "c"},
+		Sizes:     []uint8{1, 2},
+		Limit:     2 << 20,
+	})
+	check([]string{"--upper=off"}, joinOptions{Separator: "-", Repeat: 16, Timeout: time.Second, Limit: 1 << 10})
+
+	defer output.Reset()
+	if exitcode, _ := a.dispatch([]string{"join", "--sizes=1,300"}); exitcode != 1 {
+		t.Errorf("invalid value finished with code %d, expected 1", exitcode)
+	}
+	if !strings.Contains(output.String(), `invalid value "1,300" for option --sizes`) {
+		t.Errorf("invalid value isn't reported: %q", output.String())
+	}
+}
+
+func TestBind_BoolAndEscapedDefaults(t *testing.T) {
+	var options struct {
+		Upper     bool     `cli:"upper,short=u"`
+		Separator string   `cli:"separator,default=\\,"`
+		Words     []string `cli:"words,default=a\\,b c"`
+	}
+	var upper bool
+	var separator string
+	var words []string
+	a := NewApp("cli")
+	a.Strict = false
+	err := a.AddCommand(&Command{
+		Name: "join",
+		Bind: &options,
+		Handle: func(args *Args) int {
+			upper, separator, words = options.Upper, options.Separator, options.Words
+			return 0
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if exitcode, _ := a.dispatch([]string{"join", "-u", "file"}); exitcode != 0 {
+		t.Errorf("bool flag followed by an argument finished with code %d", exitcode)
+	}
+	if !upper {
+		t.Error("bool flag followed by an argument isn't set")
+	}
+	if separator != "," || !reflect.DeepEqual(words, []string{"a", "b", "c"}) {
+		t.Errorf("escaped defaults populated %q and %q", separator, words)
+	}
+}
+
+func TestBind_Invalid(t *testing.T) {
+	a := NewApp("cli")
+	for _, bind := range []interface{}{
+		joinOptions{},
+		new(string),
+		&struct {
+			M map[string]string `cli:"m"`
+		}{},
+		&struct {
+			S string `cli:"s,long=x"`
+		}{},
+		&struct {
+			S string `cli:"s,short=long"`
+		}{},
+	} {
+		if err := a.AddCommand(&Command{Name: "join", Bind: bind}); err == nil {
+			t.Errorf("invalid binding to %T is accepted", bind)
+		}
+	}
+}
@@ -1,8 +1,8 @@
 package cli
 
 import (
+	"fmt"
 	"os"
-	"strings"
 )
 
 // CmdHandler is a handling function type for functions.
@@ -12,7 +12,8 @@ type CmdHandler func(args *Args) (exitCode int)
 
 // Command represents a top-level application subcommand.
 type Command struct {
-	// Name is a [A-Za-z_0-9] identifier of up to 11 characters.
+	// Name is a [A-Za-z_0-9-] identifier of up to 11 characters,
+	// starting with a letter or a digit.
 	//
 	// Keep command names short, reasonable, catchy and
 	// easy to type. At best, keep it a single word.
@@ -41,8 +42,9 @@ type Command struct {
 	// the usage line and before the available flags block
 	// of the help entry.
 	//
-	// Try to stick to the 80 character limit, so it looks fine
-	// in the split terminal window.
+	// There's no need to wrap it by hand: paragraphs get reflowed
+	// to the terminal width. Blank lines and lines starting with
+	// a space or a tab are kept as they are.
 	Help string
 
 	// Division is the divsion displayed in help.
@@ -54,13 +56,38 @@ type Command struct {
 	// Flags are command-line options.
 	Flags []*Flag
 
+	// Positional makes the command accept positional arguments in
+	// strict mode, e.g. files, which Args.Arguments returns. The root
+	// command always accepts them.
+	Positional bool
+
+	// Bind is an optional pointer to a struct, fields of which
+	// tagged with `cli` define more flags. The fields get populated
+	// every time before Handle runs:
+	//
+	//	Separator string `cli:"separator,short=s,default=.,env=SEP" help:"Put it between strings."`
+	//
+	// Strings, bools, numbers, durations, types implementing Value
+	// through a pointer and slices of those are supported. Slice
+	// values are separated by commas or spaces. Bool flags never take
+	// the next argument as their value. The `required` option makes
+	// the flag Required. Commas in tag options are escaped with
+	// a backslash, doubled in the tag: `default=a\\,b`.
+	Bind interface{}
+
 	// Examples are annotated tips on command usage.
 	Examples []*Example
 }
 
 // AddFlag does literally what its name says.
-func (cmd *Command) AddFlag(newFlag *Flag) {
+//
+// It refuses flags violating the naming rules documented on Flag.
+func (cmd *Command) AddFlag(newFlag *Flag) error {
+	if err := validateFlag(newFlag); err != nil {
+		return err
+	}
 	cmd.Flags = append(cmd.Flags, newFlag)
+	return nil
 }
 
 // AddExample does exactly what its name says.
@@ -69,24 +96,135 @@ func (cmd *Command) AddExample(newExample *Example) {
 }
 
 // Run executes a command handler and returns corresponding exitcode.
+//
+// Global flags and the command name, if it's a subcommand of the app,
+// are skipped: it runs with the same arguments App.Run would give it.
 func (cmd Command) Run(a *App) (exitCode int) {
-	arguments := os.Args[1:]
-	if len(arguments) > 0 && !strings.HasPrefix(arguments[0], "-") {
-		// skip subcommand
-		arguments = os.Args[2:]
-	}
-	ctx, err := newContext(a, cmd.Flags, arguments)
+	globals, arguments, err := a.parseGlobals(os.Args[1:])
 	if err != nil {
 		a.printerr(err)
 		os.Exit(1)
 	}
-	exitCode = cmd.Handle(ctx)
+	if len(arguments) > 0 && cmd.Name != "" && arguments[0] == cmd.Name && a.commandByName(cmd.Name) != nil {
+		arguments = arguments[1:]
+	}
+	if a.Root != nil && a.commandByName(cmd.Name) == nil {
+		cmd.Positional = true
+	}
+	exitCode, fatal := cmd.run(a, globals, arguments)
+	if fatal {
+		os.Exit(exitCode)
+	}
 	return
 }
 
+// run executes a command handler with the arguments given. Globals
+// are the global flags preceding the subcommand name. Fatal is true
+// if the arguments are invalid.
+func (cmd Command) run(a *App, globals *Args, arguments []string) (exitCode int, fatal bool) {
+	bound, err := cmd.bindings()
+	if err != nil {
+		a.printerr(err)
+		return 1, true
+	}
+
+	own := cmd.allFlags()
+	inherited := a.inheritedFlags(own)
+	ctx, err := newContext(a, append(own, inherited...), arguments, cmd.Positional)
+	if err == nil {
+		for _, flag := range inherited {
+			if globals == nil {
+				break
+			}
+			value, given := globals.vars[flag.Name]
+			if _, ok := ctx.vars[flag.Name]; !ok && given {
+				ctx.vars[flag.Name] = value
+			}
+			ctx.counts[flag.Name] += globals.counts[flag.Name]
+		}
+		if globals != nil {
+			err = cmd.applyShadowed(globals, ctx, own, inherited)
+		}
+	}
+	if err == nil {
+		err = a.applyGlobals(ctx.vars, inherited)
+	}
+	if err == nil {
+		err = askRequired(ctx, append(own, inherited...), bound)
+	}
+	if err == nil && cmd.Bind != nil {
+		err = populate(cmd.Bind, bound, ctx)
+	}
+	if err != nil {
+		a.printerr(err)
+		return 1, true
+	}
+	defer ctx.release()
+	return cmd.Handle(ctx), false
+}
+
+// applyShadowed gives the values of global flags preceding the
+// subcommand name, but shadowed by its own flags, to the flags of
+// the same name. Values of shadowed flags named otherwise are errors.
+func (cmd Command) applyShadowed(globals, ctx *Args, own, inherited []*Flag) error {
+	for name, value := range globals.vars {
+		if lookupFlag(inherited, name) != nil {
+			continue
+		}
+		flag := lookupFlag(own, name)
+		if flag == nil || flag.Name != name {
+			return fmt.Errorf(`global option --%s isn't accepted by %s`, name, cmd.Name)
+		}
+		if _, ok := ctx.vars[name]; ok {
+			continue
+		}
+		if err := checkChoice(flag, value); err != nil {
+			return err
+		}
+		if flag.Value != nil {
+			if err := setValue(flag, value, value != ""); err != nil {
+				return err
+			}
+		}
+		ctx.vars[name] = value
+		ctx.counts[name] += globals.counts[name]
+	}
+	return nil
+}
+
+// bindings returns the flags bound to fields of the Bind struct.
+func (cmd *Command) bindings() ([]binding, error) {
+	if cmd.Bind == nil {
+		return nil, nil
+	}
+	return bindings(cmd.Bind)
+}
+
+// allFlags returns Flags followed by those bound to fields of the
+// Bind struct, unless there are flags with the same names in Flags.
+func (cmd *Command) allFlags() []*Flag {
+	bound, err := cmd.bindings()
+	if err != nil || len(bound) == 0 {
+		return cmd.Flags
+	}
+
+	flags := append([]*Flag(nil), cmd.Flags...)
+	for _, b := range bound {
+		duplicate := false
+		for _, flag := range cmd.Flags {
+			duplicate = duplicate || flag.Name == b.flag.Name
+		}
+		if !duplicate {
+			flags = append(flags, b.flag)
+		}
+	}
+	return flags
+}
+
 // Topic is some sort of a concise wiki page.
 type Topic struct {
-	// Name is a [A-Za-z_0-9] identifier of up to 11 characters.
+	// Name is a [A-Za-z_0-9-] identifier of up to 11 characters,
+	// starting with a letter or a digit.
 	//
 	// Keep topic names short, reasonable, catchy and
 	// easy to type. At best, keep it a single word.
@@ -105,16 +243,39 @@ type Topic struct {
 
 	// Text is the actual topic content.
 	//
-	// Try to stick to the 80 character limit, so it looks fine
-	// in the split terminal window.
+	// It gets reflowed to the terminal width, just like Command.Help.
 	Text string
+
+	// Topics are subtopics, displayed in the help entry of the topic.
+	//
+	// Subtopics are addressed by their path, e.g. `help net proxy`
+	// or `help net/proxy`.
+	Topics []*Topic
+
+	// SeeAlso are names of related commands and topic paths,
+	// validated when the application starts.
+	//
+	// Example: build, net/proxy
+	SeeAlso []string
+}
+
+// AddTopic adds a subtopic.
+//
+// It refuses topics violating the naming rules documented on Topic.
+func (topic *Topic) AddTopic(subtopic *Topic) error {
+	if err := validateTopic(subtopic); err != nil {
+		return err
+	}
+	topic.Topics = append(topic.Topics, subtopic)
+	return nil
 }
 
 // Flag is an optional command-line option.
 type Flag struct {
 	// A flag label without the prefix (--, -, whatever).
 	//
-	// Flag names can't contain more than 11 alphanumeric characters.
+	// Flag names can't contain more than 11 alphanumeric characters,
+	// dashes or underscores, and can't start with either of the last two.
 	Name string
 
 	// Usually the first letter of the name.
@@ -136,6 +297,34 @@ type Flag struct {
 	//
 	// Example: Limit tool output to tokens given.
 	Help string
+
+	// Required flags must be given. If they aren't and the input is
+	// a terminal, users are asked for the value, with Help as the
+	// question; otherwise it's a usage error.
+	Required bool
+
+	// Choices are the values the flag accepts, if there are limits.
+	//
+	// Example: json, yaml, toml
+	Choices []string
+
+	// Value is an optional typed value of the flag. Its Set method
+	// gets called for every occurrence of the flag on the command
+	// line, errors are reported along with the flag name.
+	Value Value
+}
+
+// Value is the interface to the dynamic value of a flag, the same as
+// flag.Value of the standard library with Type added.
+//
+// Values of the "bool" type don't require a value on the command line:
+// `--debug` is the same as `--debug=true`.
+type Value interface {
+	String() string
+	Set(string) error
+
+	// Type is a name of the type displayed in help, e.g. "size".
+	Type() string
 }
 
 // Example is an annotated use case of the command.
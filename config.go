@@ -0,0 +1,75 @@
+package cli
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// config is a parsed configuration file: values by keys by sections.
+type config map[string]map[string]string
+
+// configFile returns the path to the configuration file.
+func (a *App) configFile() string {
+	if a.ConfigFile != "" {
+		return a.ConfigFile
+	}
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, a.Name, "config")
+}
+
+// loadConfig reads a git-style configuration file:
+//
+//	# comment
+//	[alias]
+//		co = checkout -b
+//
+// Section and key names are case-insensitive. A missing file is
+// an empty configuration.
+func loadConfig(path string) (config, error) {
+	cfg := make(config)
+	if path == "" {
+		return cfg, nil
+	}
+
+	f, err := os.Open(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return cfg, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	section := ""
+	scanner := bufio.NewScanner(f)
+	for number := 1; scanner.Scan(); number++ {
+		line := strings.TrimSpace(scanner.Text())
+		switch {
+		case line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, ";"):
+		case strings.HasPrefix(line, "[") && strings.HasSuffix(line, "]"):
+			section = strings.ToLower(strings.TrimSpace(line[1 : len(line)-1]))
+		default:
+			key, value, ok := strings.Cut(line, "=")
+			key = strings.ToLower(strings.TrimSpace(key))
+			if !ok || key == "" || section == "" {
+				return nil, fmt.Errorf(`%s:%d: expected "key = value" in a section`, path, number)
+			}
+			if cfg[section] == nil {
+				cfg[section] = make(map[string]string)
+			}
+			cfg[section][key] = strings.TrimSpace(value)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
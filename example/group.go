@@ -3,6 +3,7 @@ package main
 import (
 	"fmt"
 	"github.com/ccpaging/cli"
+	"log"
 )
 
 func main() {
@@ -87,10 +88,12 @@ func main() {
 		Handle: Example_handler,
 	}
 
-	demo.AddCommand(joinCmd)
 	// Division displayed in help
-	demo.AddCommand(joinCmd1)
-	demo.AddCommand(joinCmd2)
+	for _, cmd := range []*cli.Command{joinCmd, joinCmd1, joinCmd2} {
+		if err := demo.AddCommand(cmd); err != nil {
+			log.Fatal(err)
+		}
+	}
 	demo.Run()
 }
 
@@ -3,6 +3,7 @@ package main
 import (
 	"fmt"
 	"github.com/ccpaging/cli"
+	"log"
 	"strings"
 )
 
@@ -50,7 +51,9 @@ func main() {
 		},
 	}
 
-	demo.AddCommand(joinCmd)
+	if err := demo.AddCommand(joinCmd); err != nil {
+		log.Fatal(err)
+	}
 	demo.Run()
 }
 
@@ -50,7 +50,9 @@ func main() {
 		},
 	}
 
-	//demo.AddCommand(joinCmd)
+	//if err := demo.AddCommand(joinCmd); err != nil {
+	//	log.Fatal(err)
+	//}
 	demo.Run()
 }
 
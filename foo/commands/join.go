@@ -50,5 +50,7 @@ var JoinCmd = &cli.Command{
 }
 
 func init() {
-	App.AddCommand(JoinCmd)
+	if err := App.AddCommand(JoinCmd); err != nil {
+		panic(err)
+	}
 }
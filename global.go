@@ -0,0 +1,148 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Built-in global flags every application accepts.
+var (
+	colorFlag = &Flag{
+		Name:  "color",
+		Usage: "--color[=WHEN]",
+		Help:  "Colorize the output: auto, always or never.",
+		Value: colorValue{},
+	}
+	noPagerFlag = &Flag{
+		Name:  "no-pager",
+		Usage: "--no-pager",
+		Help:  "Don't page long help through $PAGER.",
+		Value: switchValue{},
+	}
+)
+
+// colorValue checks --color values while the arguments are parsed.
+// The value is optional and never taken from the next argument, so
+// it must be joined with an equals sign: --color=never.
+type colorValue struct{}
+
+func (colorValue) String() string   { return "" }
+func (colorValue) Type() string     { return "when" }
+func (colorValue) IsBoolFlag() bool { return true }
+
+func (colorValue) Set(value string) error {
+	switch value {
+	case "", ColorAuto, ColorAlways, ColorNever:
+		return nil
+	}
+	return fmt.Errorf(`expected %s, %s or %s`, ColorAuto, ColorAlways, ColorNever)
+}
+
+// AddFlag adds a global flag, accepted before or after the subcommand
+// name by every command. Commands may have flags of the same name,
+// which then get the value given before the subcommand name.
+//
+// It refuses flags violating the naming rules documented on Flag.
+func (a *App) AddFlag(newFlag *Flag) error {
+	if err := validateFlag(newFlag); err != nil {
+		return err
+	}
+	a.Flags = append(a.Flags, newFlag)
+	return nil
+}
+
+// globalFlags returns Flags followed by the built-in global flags.
+func (a *App) globalFlags() []*Flag {
+	return append(append([]*Flag(nil), a.Flags...), colorFlag, noPagerFlag)
+}
+
+// inheritedFlags returns the global flags a command inherits: those
+// none of its own flags shadows by name or short name.
+func (a *App) inheritedFlags(own []*Flag) []*Flag {
+	var inherited []*Flag
+	for _, global := range a.globalFlags() {
+		shadowed := false
+		for _, flag := range own {
+			shadowed = shadowed || flag.Name == global.Name || flag.Name == global.Short ||
+				(flag.Short != "" && (flag.Short == global.Name || flag.Short == global.Short))
+		}
+		if !shadowed {
+			inherited = append(inherited, global)
+		}
+	}
+	return inherited
+}
+
+// lookupFlag returns the flag with the name or short name given.
+func lookupFlag(flags []*Flag, name string) *Flag {
+	for _, flag := range flags {
+		if flag.Name == name || (flag.Short != "" && flag.Short == name) {
+			return flag
+		}
+	}
+	return nil
+}
+
+// repeatedShort returns the flag whose short name is repeated in name,
+// e.g. -vvv.
+func repeatedShort(flags []*Flag, name string) *Flag {
+	for _, flag := range flags {
+		n := len(flag.Short)
+		if n > 0 && len(name) > n && len(name)%n == 0 && name == strings.Repeat(flag.Short, len(name)/n) {
+			return flag
+		}
+	}
+	return nil
+}
+
+// parseGlobals consumes the global flags preceding the subcommand
+// name. Values of flags other than switches are either joined with an
+// equals sign or given as the next argument, e.g. --config file.toml.
+func (a *App) parseGlobals(arguments []string) (globals *Args, rest []string, err error) {
+	flags := a.globalFlags()
+	globals = &Args{app: a, vars: make(map[string]string), counts: make(map[string]int)}
+	for len(arguments) > 0 && isOption(arguments[0]) {
+		name, _, joined := strings.Cut(strings.TrimLeft(arguments[0], "-"), "=")
+		flag := lookupFlag(flags, name)
+		if flag == nil && repeatedShort(flags, name) == nil {
+			break
+		}
+
+		n := 1
+		if flag != nil && !joined && !isBoolValue(flag) && len(arguments) > 1 && !isOption(arguments[1]) {
+			n = 2
+		}
+		parsed, err := newContext(a, flags, arguments[:n], false)
+		if err != nil {
+			return nil, nil, err
+		}
+		for name, value := range parsed.vars {
+			globals.vars[name] = value
+		}
+		for name, n := range parsed.counts {
+			globals.counts[name] += n
+		}
+		arguments = arguments[n:]
+	}
+
+	return globals, arguments, a.applyGlobals(globals.vars, flags)
+}
+
+// applyGlobals applies the values of the built-in global flags.
+func (a *App) applyGlobals(vars map[string]string, flags []*Flag) error {
+	for _, flag := range flags {
+		value, ok := vars[flag.Name]
+		if !ok {
+			continue
+		}
+		switch flag {
+		case colorFlag:
+			if err := a.setColor(value); err != nil {
+				return err
+			}
+		case noPagerFlag:
+			a.Pager = false
+		}
+	}
+	return nil
+}
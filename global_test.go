@@ -0,0 +1,124 @@
+package cli
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestApp_Flags(t *testing.T) {
+	var got map[string]string
+	a := NewApp("cli")
+	a.Color = ColorNever
+	a.Flags = []*Flag{
+		{Name: "config", Short: "c", Help: "Read settings from the file."},
+		{Name: "verbose", Short: "v", Value: switchValue{}},
+	}
+	a.Commands = []*Command{{
+		Name:  "open",
+		Flags: []*Flag{{Name: "config", Help: "Open the configuration."}},
+		Handle: func(args *Args) int {
+			got = args.Variables()
+			return 0
+		},
+	}}
+
+	check := func(arguments []string, expected map[string]string) {
+		if exitcode, _ := a.dispatch(arguments); exitcode != 0 {
+			t.Errorf("%v finished with code %d", arguments, exitcode)
+		}
+		if len(got) != len(expected) {
+			t.Errorf("%v resulted in %v, expected %v", arguments, got, expected)
+			return
+		}
+		for name, value := range expected {
+			if got[name] != value {
+				t.Errorf("%v resulted in %v, expected %v", arguments, got, expected)
+			}
+		}
+	}
+
+	check([]string{"-v", "open"}, map[string]string{"verbose": ""})
+	check([]string{"open", "--verbose", "--color=always"}, map[string]string{"verbose": "", "color": "always"})
+	check([]string{"--no-pager", "open", "-v=false"}, map[string]string{"no-pager": "", "verbose": "false"})
+
+	// the command's own --config shadows the global one, given before
+	// the command name it goes to the command's flag, -c isn't
+	// accepted by the command at all
+	check([]string{"--config=global", "open"}, map[string]string{"config": "global"})
+	check([]string{"-c=global", "open"}, map[string]string{"config": "global"})
+	check([]string{"--config=global", "open", "--config", "local"}, map[string]string{"config": "local"})
+	check([]string{"--config", "global", "open"}, map[string]string{"config": "global"})
+	check([]string{"-c", "global", "-v", "open"}, map[string]string{"config": "global", "verbose": ""})
+	check([]string{"open", "--config", "local"}, map[string]string{"config": "local"})
+	defer output.Reset()
+	if exitcode, _ := a.dispatch([]string{"open", "-c", "local"}); exitcode != 1 {
+		t.Errorf("shadowed short flag finished with code %d, expected 1", exitcode)
+	}
+
+	a.Commands = append(a.Commands, &Command{Name: "close", Flags: []*Flag{{Name: "c"}}, Handle: a.Commands[0].Handle})
+	if exitcode, _ := a.dispatch([]string{"--config=global", "close"}); exitcode != 1 {
+		t.Errorf("global shadowed by another name finished with code %d, expected 1", exitcode)
+	}
+	if !strings.Contains(output.String(), "global option --config isn't accepted by close") {
+		t.Errorf("global shadowed by another name isn't reported: %q", output.String())
+	}
+
+	if a.Pager || a.Color != ColorAlways {
+		t.Errorf("built-in global flags aren't applied: pager %v, color %s", a.Pager, a.Color)
+	}
+
+	help := a.globalHelp()
+	expected := "\x1b[1mThe global options are:\x1b[0m\n\n" +
+		"\t\x1b[33m-c, --config=\"\"\x1b[0m\n\t\tRead settings from the file.\n" +
+		"\t\x1b[33m-v, --verbose\x1b[0m\n"
+	if !strings.Contains(help, expected) || !strings.Contains(help, "--color[=WHEN]") {
+		t.Errorf("global options aren't listed: %q", help)
+	}
+
+	if err := a.AddFlag(&Flag{Name: "-x"}); err == nil {
+		t.Error("invalid global flag is accepted")
+	}
+}
+
+func TestApp_BuiltinFlagsArguments(t *testing.T) {
+	var got []string
+	a := NewApp("demo")
+	a.Strict = false
+	a.Commands = []*Command{{
+		Name: "cat",
+		Handle: func(args *Args) int {
+			got = args.Arguments()
+			return 0
+		},
+	}}
+
+	check := func(arguments []string, expected ...string) {
+		t.Helper()
+		got = nil
+		a.Pager, a.Color = true, ""
+		if exitcode, _ := a.dispatch(arguments); exitcode != 0 {
+			t.Errorf("%v finished with code %d", arguments, exitcode)
+		}
+		if !reflect.DeepEqual(got, expected) {
+			t.Errorf("%v resulted in arguments %q, expected %q", arguments, got, expected)
+		}
+	}
+
+	check([]string{"cat", "-"}, "-")
+	check([]string{"cat", "--", "--no-pager", "-"}, "--no-pager", "-")
+	if !a.Pager || a.Color != "" {
+		t.Errorf("arguments are taken for built-in flags: pager %v, color %q", a.Pager, a.Color)
+	}
+	check([]string{"cat", "a.txt", "--no-pager", "b.txt"}, "a.txt", "b.txt")
+	check([]string{"cat", "a.txt", "--color", "b.txt"}, "a.txt", "b.txt")
+	if a.Color != ColorAlways {
+		t.Errorf("bare --color resulted in color %q", a.Color)
+	}
+	check([]string{"cat", "--color=never", "a.txt"}, "a.txt")
+
+	defer output.Reset()
+	if exitcode, _ := a.dispatch([]string{"cat", "--color=sometimes"}); exitcode != 1 {
+		t.Errorf("invalid --color value finished with code %d, expected 1", exitcode)
+	}
+}
@@ -1,73 +1,10 @@
 package cli
 
 import (
-	"bytes"
+	"sort"
 	"strings"
-	"text/template"
 )
 
-const globalHelpTemplate string = `{{.Brief}}
-
-Usage:
-
-	{{.Name}} {{if .Commands}}command [arguments]{{end}}
-
-{{if .Commands}}The commands are:
-{{range .Commands}}
-{{if .Division}}{{.Division | printf "\n%s\n\n"}}{{end}}	{{.Name | printf "%-11s"}} {{.Brief}}{{end}}
-
-Use "{{.Name}} help [command]" for more information about a command.{{end}}
-{{if .Topics}}
-Additional help topics:
-{{range .Topics}}
-	{{.Name | printf "%-11s"}} {{.Brief}}{{end}}
-
-Use "{{.Name}} help [topic]" for more information about a topic.
-{{end}}`
-
-const commandHelpTemplate string = `Usage: {{commandUsage .Command}}
-
-{{.Help}}
-{{if .Flags}}
-Available options:
-{{range .Flags}}
-	{{flagUsage . false}}
-		{{.Help | tabout}}{{end}}{{end}}
-{{if .Examples}}
-Examples:
-{{$app := .App}}{{$cmd := .Name}}{{range .Examples}}
-	$ {{$app}} {{if $cmd}}{{$cmd}} {{end}}{{.Usecase}}
-		{{.Description | tabout}}
-{{end}}{{end}}`
-
-func templated(canvas string, data interface{}) string {
-	t := template.New("")
-	t.Funcs(template.FuncMap{
-		"tabout":       alignMultilineHelp,
-		"commandUsage": commandUsage,
-		"flagUsage":    flagUsage,
-	})
-	template.Must(t.Parse(canvas))
-
-	var b bytes.Buffer
-
-	err := t.Execute(&b, data)
-	if err != nil {
-		panic(err)
-	}
-
-	output := b.String()
-
-	// TODO: Fix this nasty workaround for templating ASAP!
-	output = strings.Replace(output, "\n\n\n", "", -1)
-
-	return output
-}
-
-func alignMultilineHelp(text string) string {
-	return strings.Replace(text, "\n", "\n\t\t", -1)
-}
-
 func commandUsage(command *Command) string {
 	if command.Usage != "" {
 		if command.Name != "" {
@@ -77,7 +14,7 @@ func commandUsage(command *Command) string {
 	}
 
 	usage := command.Name
-	for _, flag := range command.Flags {
+	for _, flag := range command.allFlags() {
 		usage += " [" + flagUsage(flag, true) + "]"
 	}
 
@@ -103,24 +40,157 @@ func flagUsage(flag *Flag, tiny bool) string {
 	}
 
 	usage := "--" + flag.Name + "=\"\""
+	if flag.Value != nil {
+		usage = "--" + flag.Name
+		if !isBoolValue(flag) {
+			usage += "=" + flag.Value.Type()
+		}
+	}
 
 	return short + usage
 }
 
 func (a *App) globalHelp() string {
-	return templated(globalHelpTemplate, struct {
-		*App
-	}{
-		a,
-	})
+	theme := a.theme(Stdout)
+	page := newLayout(theme)
+	page.add(paragraph(a.Brief))
+
+	usage := a.Name
+	if a.Root != nil {
+		usage = strings.TrimRight(a.Name+" "+commandUsage(a.Root), " ")
+	}
+	if len(a.Commands) > 0 {
+		if a.Root != nil {
+			usage += "\n" + a.Name
+		}
+		usage += " command [arguments]"
+	}
+	page.section("Usage:", indented(usage))
+
+	if a.Root != nil {
+		page.add(paragraph(a.Root.Help))
+
+		options := definitionList{termStyle: theme.Flag}
+		for _, flag := range a.Root.allFlags() {
+			options.items = append(options.items, definition{flagUsage(flag, false), flag.Help})
+		}
+		page.section("Available options:", options)
+	}
+
+	if len(a.Commands) > 0 {
+		page.heading("The commands are:")
+
+		// divisions share the name column
+		headings := []string{""}
+		divisions := [][]definition{nil}
+		var all []definition
+		for _, command := range a.Commands {
+			if command.Division != "" {
+				headings = append(headings, command.Division)
+				divisions = append(divisions, nil)
+			}
+			all = append(all, definition{command.Name, command.Brief})
+			divisions[len(divisions)-1] = append(divisions[len(divisions)-1], all[len(all)-1])
+		}
+		if a.Shell {
+			all = append(all, definition{"shell", "runs commands typed interactively"})
+			divisions[len(divisions)-1] = append(divisions[len(divisions)-1], all[len(all)-1])
+		}
+		if a.Scripts {
+			all = append(all, definition{"run-script", "runs commands from a file"})
+			divisions[len(divisions)-1] = append(divisions[len(divisions)-1], all[len(all)-1])
+		}
+
+		for i, items := range divisions {
+			if headings[i] != "" {
+				page.heading(headings[i])
+			}
+			page.add(definitionList{items: items, columned: true, termStyle: theme.Name, aligned: all})
+		}
+
+		page.add(paragraph(`Use "` + a.Name + ` help [command]" for more information about a command.`))
+	}
+
+	options := definitionList{termStyle: theme.Flag}
+	for _, flag := range a.globalFlags() {
+		options.items = append(options.items, definition{flagUsage(flag, false), flag.Help})
+	}
+	page.section("The global options are:", options)
+
+	if aliases, _ := a.aliases(); len(aliases) > 0 {
+		list := definitionList{columned: true, termStyle: theme.Name}
+		for _, name := range aliasNames(aliases) {
+			list.items = append(list.items, definition{name, aliases[name]})
+		}
+		page.section("The aliases are:", list)
+	}
+
+	if plugins := a.plugins(); len(plugins) > 0 {
+		var names []string
+		for name := range plugins {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		list := definitionList{columned: true, termStyle: theme.Name}
+		for _, name := range names {
+			list.items = append(list.items, definition{name, ""})
+		}
+		page.section("The plugin commands are:", list)
+	}
+
+	if len(a.Topics) > 0 {
+		list := definitionList{columned: true, termStyle: theme.Name}
+		for _, topic := range a.Topics {
+			list.items = append(list.items, definition{topic.Name, topic.Brief})
+		}
+		page.section("Additional help topics:", list)
+
+		page.add(paragraph(`Use "` + a.Name + ` help [topic]" for more information about a topic.`))
+	}
+
+	return page.String()
 }
 
 func (a *App) commandHelp(command *Command) string {
-	return templated(commandHelpTemplate, struct {
-		*Command
-		App string
-	}{
-		command,
-		a.Name,
-	})
+	theme := a.theme(Stdout)
+	page := newLayout(theme)
+	page.add(paragraph(paint(theme.Heading, "Usage:") + " " + commandUsage(command)))
+	page.add(paragraph(command.Help))
+
+	options := definitionList{termStyle: theme.Flag}
+	for _, flag := range command.allFlags() {
+		options.items = append(options.items, definition{flagUsage(flag, false), flag.Help})
+	}
+	page.section("Available options:", options)
+
+	examples := definitionList{spaced: true}
+	for _, example := range command.Examples {
+		usecase := "$ " + a.Name + " "
+		if command.Name != "" {
+			usecase += command.Name + " "
+		}
+		examples.items = append(examples.items, definition{usecase + example.Usecase, example.Description})
+	}
+	page.section("Examples:", examples)
+
+	return page.String()
+}
+
+func (a *App) topicHelp(topic *Topic) string {
+	theme := a.theme(Stdout)
+	page := newLayout(theme)
+	page.add(paragraph(topic.Text))
+
+	subtopics := definitionList{columned: true, termStyle: theme.Name}
+	for _, subtopic := range topic.Topics {
+		subtopics.items = append(subtopics.items, definition{subtopic.Name, subtopic.Brief})
+	}
+	page.section("Subtopics:", subtopics)
+
+	if len(topic.SeeAlso) > 0 {
+		page.section("See also:", indented(strings.Join(topic.SeeAlso, ", ")))
+	}
+
+	return page.String()
 }
@@ -0,0 +1,199 @@
+package cli
+
+import (
+	"reflect"
+	"testing"
+)
+
+func checkHelp(t *testing.T, c, expected, received string) {
+	t.Helper()
+	if received != expected {
+		t.Errorf(`case "%s" produced unexpected help:`, c)
+		t.Logf("- expected:\n%q", expected)
+		t.Logf("- recieved:\n%q", received)
+	}
+}
+
+func TestHelp_KeepsBlankLines(t *testing.T) {
+	a := NewApp("cli")
+	cmd := &Command{
+		Name:  "sync",
+		Usage: "[dirs]",
+		Help:  "First paragraph.\n\n\nSecond paragraph,\n\tindented line.",
+		Flags: []*Flag{
+			{Name: "dry", Help: "Only print.\n\nNothing is touched."},
+		},
+	}
+
+	expected := "Usage: sync [dirs]\n" +
+		"\n" +
+		"First paragraph.\n" +
+		"\n" +
+		"\n" +
+		"Second paragraph,\n" +
+		"\tindented line.\n" +
+		"\n" +
+		"Available options:\n" +
+		"\n" +
+		"\t--dry=\"\"\n" +
+		"\t\tOnly print.\n" +
+		"\n" +
+		"\t\tNothing is touched.\n"
+
+	checkHelp(t, "blank lines", expected, a.commandHelp(cmd))
+}
+
+func TestHelp_Divisions(t *testing.T) {
+	a := NewApp("cli")
+	a.Brief = "cli is a thing"
+	a.AddCommand(&Command{Name: "open", Brief: "opens smth"})
+	a.Commands = append(a.Commands, &Command{Name: "averylongname", Brief: "has a long name"})
+	a.AddCommand(&Command{Name: "close", Brief: "closes smth", Division: "Closing"})
+	a.AddCommand(&Command{Name: "shut", Brief: "shuts smth"})
+
+	expected := `cli is a thing
+
+Usage:
+
+	cli command [arguments]
+
+The commands are:
+
+	open          opens smth
+	averylongname has a long name
+
+Closing
+
+	close         closes smth
+	shut          shuts smth
+
+Use "cli help [command]" for more information about a command.
+
+The global options are:
+
+	--color[=WHEN]
+		Colorize the output: auto, always or never.
+	--no-pager
+		Don't page long help through $PAGER.
+`
+
+	checkHelp(t, "divisions", expected, a.globalHelp())
+}
+
+func TestHelp_Empty(t *testing.T) {
+	a := NewApp("cli")
+	checkHelp(t, "no commands", "Usage:\n\n\tcli\n\n"+
+		"The global options are:\n\n"+
+		"\t--color[=WHEN]\n\t\tColorize the output: auto, always or never.\n"+
+		"\t--no-pager\n\t\tDon't page long help through $PAGER.\n", a.globalHelp())
+	checkHelp(t, "bare command", "Usage: bare\n", a.commandHelp(&Command{Name: "bare"}))
+}
+
+func TestHelp_Wrapping(t *testing.T) {
+	t.Setenv("COLUMNS", "40")
+
+	a := NewApp("cli")
+	a.Commands = []*Command{
+		{Name: "open", Brief: "opens smth in a rather lengthy manner"},
+		{Name: "configuration", Brief: "edits settings"},
+	}
+
+	expected := `Usage:
+
+	cli command [arguments]
+
+The commands are:
+
+	open        opens smth in a
+	            rather lengthy
+	            manner
+	configuration
+	            edits settings
+
+Use "cli help [command]" for more
+information about a command.
+
+The global options are:
+
+	--color[=WHEN]
+		Colorize the output:
+		auto, always or never.
+	--no-pager
+		Don't page long help
+		through $PAGER.
+`
+	checkHelp(t, "commands", expected, a.globalHelp())
+
+	cmd := &Command{
+		Name: "open",
+		Help: "Opens smth, wrapping\nthe text to the\nwidth of the terminal.\n\n" +
+			"- a list item that goes on\n- short one\n\n\tkept   as is",
+		Flags: []*Flag{{Name: "all", Help: "Open every single thing there is."}},
+	}
+
+	expected = `Usage: open [--all]
+
+Opens smth, wrapping the text to the
+width of the terminal.
+
+- a list item that goes on
+- short one
+
+	kept   as is
+
+Available options:
+
+	--all=""
+		Open every single thing
+		there is.
+`
+	checkHelp(t, "command", expected, a.commandHelp(cmd))
+}
+
+func TestHelp_Columns(t *testing.T) {
+	a := NewApp("cli")
+	a.Commands = []*Command{
+		{Name: "open", Brief: "opens smth"},
+		{Name: "configuration", Brief: "edits settings"},
+	}
+	a.Topics = []*Topic{
+		{Name: "writing", Brief: "how to write"},
+	}
+
+	expected := `Usage:
+
+	cli command [arguments]
+
+The commands are:
+
+	open          opens smth
+	configuration edits settings
+
+Use "cli help [command]" for more information about a command.
+
+The global options are:
+
+	--color[=WHEN]
+		Colorize the output: auto, always or never.
+	--no-pager
+		Don't page long help through $PAGER.
+
+Additional help topics:
+
+	writing     how to write
+
+Use "cli help [topic]" for more information about a topic.
+`
+	checkHelp(t, "long command name", expected, a.globalHelp())
+}
+
+func TestHelp_LongTermWithoutDescription(t *testing.T) {
+	list := definitionList{columned: true, items: []definition{
+		{"a-rather-long-command-name", ""},
+		{"open", "opens smth"},
+	}}
+	expected := []string{"\ta-rather-long-command-name", "\topen        opens smth"}
+	if lines := list.lines(80); !reflect.DeepEqual(lines, expected) {
+		t.Errorf("unexpected lines: %q", lines)
+	}
+}
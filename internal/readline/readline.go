@@ -0,0 +1,310 @@
+// Package readline is a minimal line editor for interactive shells.
+//
+// On a terminal it supports cursor movement, the usual emacs-style
+// shortcuts, history and tab completion. Otherwise lines are read as
+// they are, so shells can be scripted.
+package readline
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"unicode"
+
+	"github.com/ccpaging/cli/internal/term"
+)
+
+// ErrInterrupt is returned when the user presses Ctrl-C.
+var ErrInterrupt = errors.New("interrupted")
+
+// Control keys.
+const (
+	keyCtrlA     = 1
+	keyCtrlB     = 2
+	keyCtrlC     = 3
+	keyCtrlD     = 4
+	keyCtrlE     = 5
+	keyCtrlF     = 6
+	keyBackspace = 8
+	keyTab       = 9
+	keyLF        = 10
+	keyCtrlK     = 11
+	keyCtrlL     = 12
+	keyCR        = 13
+	keyCtrlN     = 14
+	keyCtrlP     = 16
+	keyCtrlU     = 21
+	keyCtrlW     = 23
+	keyEscape    = 27
+	keyDelete    = 127
+)
+
+// Editor reads lines from In, echoing them to Out.
+type Editor struct {
+	In  io.Reader
+	Out io.Writer
+
+	// Prompt is displayed before every line.
+	Prompt string
+
+	// History is the list of previous lines, the latest goes last.
+	History []string
+
+	// Complete returns the candidates the word being typed could be
+	// completed to. Line is everything before the cursor.
+	Complete func(line string) []string
+
+	reader *bufio.Reader
+}
+
+// ReadLine reads a single line, without the line break.
+//
+// It returns io.EOF when input is over or the user presses Ctrl-D on
+// an empty line, and ErrInterrupt if the user presses Ctrl-C.
+func (e *Editor) ReadLine() (string, error) {
+	if e.reader == nil {
+		e.reader = bufio.NewReader(e.In)
+	}
+
+	f, ok := e.In.(*os.File)
+	if !ok || !term.IsTerminal(f.Fd()) {
+		return e.readPlain()
+	}
+
+	state, err := term.MakeRaw(f.Fd())
+	if err != nil {
+		return e.readPlain()
+	}
+	defer term.Restore(f.Fd(), state)
+
+	return e.readRaw()
+}
+
+func (e *Editor) readPlain() (string, error) {
+	line, err := e.reader.ReadString('\n')
+	if err == io.EOF && line != "" {
+		err = nil
+	}
+	return strings.TrimRight(line, "\r\n"), err
+}
+
+// line is the state of the line being edited.
+type line struct {
+	text   []rune
+	cursor int
+}
+
+func (l *line) insert(runes ...rune) {
+	text := make([]rune, 0, len(l.text)+len(runes))
+	text = append(text, l.text[:l.cursor]...)
+	text = append(text, runes...)
+	l.text = append(text, l.text[l.cursor:]...)
+	l.cursor += len(runes)
+}
+
+// erase removes runes between from and to, leaving the cursor at from.
+func (l *line) erase(from, to int) {
+	if from < 0 || to > len(l.text) || from >= to {
+		return
+	}
+	l.text = append(l.text[:from], l.text[to:]...)
+	l.cursor = from
+}
+
+// wordStart returns the position the word before the cursor starts at.
+func (l *line) wordStart() int {
+	i := l.cursor
+	for i > 0 && unicode.IsSpace(l.text[i-1]) {
+		i--
+	}
+	for i > 0 && !unicode.IsSpace(l.text[i-1]) {
+		i--
+	}
+	return i
+}
+
+func (e *Editor) readRaw() (string, error) {
+	var (
+		l       line
+		history = len(e.History)
+		draft   []rune
+	)
+
+	recall := func(i int) {
+		if i < 0 || i > len(e.History) {
+			return
+		}
+		if history == len(e.History) {
+			draft = append([]rune(nil), l.text...)
+		}
+		history = i
+		if i == len(e.History) {
+			l.text = draft
+		} else {
+			l.text = []rune(e.History[i])
+		}
+		l.cursor = len(l.text)
+	}
+
+	e.redraw(&l)
+	for {
+		r, _, err := e.reader.ReadRune()
+		if err != nil {
+			return "", err
+		}
+
+		switch r {
+		case keyCR, keyLF:
+			fmt.Fprint(e.Out, "\r\n")
+			return string(l.text), nil
+		case keyCtrlC:
+			fmt.Fprint(e.Out, "^C\r\n")
+			return "", ErrInterrupt
+		case keyCtrlD:
+			if len(l.text) == 0 {
+				fmt.Fprint(e.Out, "\r\n")
+				return "", io.EOF
+			}
+			l.erase(l.cursor, l.cursor+1)
+		case keyCtrlA:
+			l.cursor = 0
+		case keyCtrlE:
+			l.cursor = len(l.text)
+		case keyCtrlB:
+			if l.cursor > 0 {
+				l.cursor--
+			}
+		case keyCtrlF:
+			if l.cursor < len(l.text) {
+				l.cursor++
+			}
+		case keyCtrlK:
+			l.erase(l.cursor, len(l.text))
+		case keyCtrlU:
+			l.erase(0, l.cursor)
+		case keyCtrlW:
+			l.erase(l.wordStart(), l.cursor)
+		case keyCtrlL:
+			fmt.Fprint(e.Out, "\x1b[H\x1b[2J")
+		case keyCtrlP:
+			recall(history - 1)
+		case keyCtrlN:
+			recall(history + 1)
+		case keyBackspace, keyDelete:
+			l.erase(l.cursor-1, l.cursor)
+		case keyTab:
+			e.complete(&l)
+		case keyEscape:
+			switch e.readEscape() {
+			case 'A':
+				recall(history - 1)
+			case 'B':
+				recall(history + 1)
+			case 'C':
+				if l.cursor < len(l.text) {
+					l.cursor++
+				}
+			case 'D':
+				if l.cursor > 0 {
+					l.cursor--
+				}
+			case 'H':
+				l.cursor = 0
+			case 'F':
+				l.cursor = len(l.text)
+			case '~':
+				l.erase(l.cursor, l.cursor+1)
+			}
+		default:
+			if unicode.IsPrint(r) {
+				l.insert(r)
+			}
+		}
+		e.redraw(&l)
+	}
+}
+
+// readEscape reads the rest of an escape sequence and returns its
+// final character. Home, End and Delete sequences of the `ESC [ n ~`
+// form are translated to 'H', 'F' and '~'.
+func (e *Editor) readEscape() rune {
+	r, _, err := e.reader.ReadRune()
+	if err != nil || (r != '[' && r != 'O') {
+		return 0
+	}
+
+	var param []rune
+	for {
+		r, _, err = e.reader.ReadRune()
+		if err != nil {
+			return 0
+		}
+		if (r < '0' || r > '9') && r != ';' {
+			break
+		}
+		param = append(param, r)
+	}
+
+	if r == '~' {
+		switch string(param) {
+		case "1", "7":
+			return 'H'
+		case "4", "8":
+			return 'F'
+		case "3":
+			return '~'
+		}
+		return 0
+	}
+	return r
+}
+
+// complete completes the word before the cursor. If there are many
+// candidates, their common prefix gets inserted and the candidates
+// are listed below the line.
+func (e *Editor) complete(l *line) {
+	if e.Complete == nil {
+		return
+	}
+
+	start := l.cursor
+	for start > 0 && !unicode.IsSpace(l.text[start-1]) {
+		start--
+	}
+	word := string(l.text[start:l.cursor])
+
+	candidates := e.Complete(string(l.text[:l.cursor]))
+	if len(candidates) == 0 {
+		return
+	}
+
+	prefix := []rune(candidates[0])
+	for _, candidate := range candidates[1:] {
+		for !strings.HasPrefix(candidate, string(prefix)) {
+			prefix = prefix[:len(prefix)-1]
+		}
+	}
+	if len(candidates) == 1 {
+		prefix = append(prefix, ' ')
+	}
+
+	if typed := []rune(word); strings.HasPrefix(string(prefix), word) && len(prefix) > len(typed) {
+		l.insert(prefix[len(typed):]...)
+		return
+	}
+
+	if len(candidates) > 1 {
+		fmt.Fprint(e.Out, "\r\n"+strings.Join(candidates, "  ")+"\r\n")
+	}
+}
+
+// redraw displays the prompt and the line, placing the cursor.
+func (e *Editor) redraw(l *line) {
+	fmt.Fprint(e.Out, "\r\x1b[K", e.Prompt, string(l.text))
+	if n := len(l.text) - l.cursor; n > 0 {
+		fmt.Fprintf(e.Out, "\x1b[%dD", n)
+	}
+}
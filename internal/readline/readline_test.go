@@ -0,0 +1,75 @@
+package readline
+
+import (
+	"bufio"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestReadRaw(t *testing.T) {
+	check := func(c, input, expected string) {
+		e := &Editor{
+			Out:     io.Discard,
+			History: []string{"first", "second"},
+			Complete: func(line string) []string {
+				var matches []string
+				for _, word := range []string{"open", "opening", "close"} {
+					if strings.HasPrefix(word, line[strings.LastIndex(line, " ")+1:]) {
+						matches = append(matches, word)
+					}
+				}
+				return matches
+			},
+			reader: bufio.NewReader(strings.NewReader(input)),
+		}
+
+		line, err := e.readRaw()
+		if err != nil {
+			t.Errorf(`case "%s" failed: %s`, c, err)
+			return
+		}
+		if line != expected {
+			t.Errorf(`case "%s" resulted in %q, expected %q`, c, line, expected)
+		}
+	}
+
+	check("plain", "hello\r", "hello")
+	check("insert", "abc\x02\x02X\r", "aXbc")
+	check("arrows", "abc\x1b[D\x1b[DX\x1b[CY\r", "aXbYc")
+	check("home and end", "bc\x1b[Ha\x1b[Fd\r", "abcd")
+	check("backspace", "abcd\x7f\x7f\r", "ab")
+	check("delete", "abc\x01\x1b[3~\r", "bc")
+	check("kill", "hello world\x02\x02\x02\x0b\r", "hello wo")
+	check("kill word", "hello world\x17\r", "hello ")
+	check("history", "\x1b[A\x1b[A\x1b[A\x1b[B!\r", "second!")
+	check("history draft", "draft\x10\x0e\r", "draft")
+	check("complete", "cl\t\r", "close ")
+	check("common prefix", "x op\t\r", "x open")
+	check("unicode", "größe\x7f\r", "größ")
+}
+
+func TestReadRaw_Interrupt(t *testing.T) {
+	e := &Editor{Out: io.Discard, reader: bufio.NewReader(strings.NewReader("abc\x03"))}
+	if _, err := e.readRaw(); err != ErrInterrupt {
+		t.Errorf("Ctrl-C resulted in %v", err)
+	}
+
+	e = &Editor{Out: io.Discard, reader: bufio.NewReader(strings.NewReader("\x04"))}
+	if _, err := e.readRaw(); err != io.EOF {
+		t.Errorf("Ctrl-D resulted in %v", err)
+	}
+}
+
+func TestReadLine_Plain(t *testing.T) {
+	e := &Editor{In: strings.NewReader("one\r\ntwo"), Out: io.Discard}
+
+	for _, expected := range []string{"one", "two"} {
+		if line, err := e.ReadLine(); err != nil || line != expected {
+			t.Errorf("read %q (%v), expected %q", line, err, expected)
+		}
+	}
+	if _, err := e.ReadLine(); err != io.EOF {
+		t.Errorf("read past the input: %v", err)
+	}
+}
@@ -0,0 +1,6 @@
+// Package term provides a tiny subset of terminal handling needed by
+// cli, without depending on anything outside of the standard library.
+package term
+
+// DefaultWidth is the width assumed when it can't be detected.
+const DefaultWidth = 80
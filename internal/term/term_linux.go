@@ -0,0 +1,83 @@
+package term
+
+import (
+	"syscall"
+	"unsafe"
+)
+
+type winsize struct {
+	rows, cols, xpixel, ypixel uint16
+}
+
+// State is a terminal state to restore.
+type State struct {
+	termios syscall.Termios
+}
+
+func ioctl(fd, request uintptr, t *syscall.Termios) error {
+	_, _, errno := syscall.Syscall(syscall.SYS_IOCTL, fd, request, uintptr(unsafe.Pointer(t)))
+	if errno != 0 {
+		return errno
+	}
+	return nil
+}
+
+// IsTerminal reports whether fd refers to a terminal.
+func IsTerminal(fd uintptr) bool {
+	var t syscall.Termios
+	return ioctl(fd, syscall.TCGETS, &t) == nil
+}
+
+// MakeRaw puts the terminal into raw mode: input is available byte
+// by byte, without echo and signals. It returns the previous state.
+func MakeRaw(fd uintptr) (*State, error) {
+	var state State
+	if err := ioctl(fd, syscall.TCGETS, &state.termios); err != nil {
+		return nil, err
+	}
+
+	raw := state.termios
+	raw.Iflag &^= syscall.IGNBRK | syscall.BRKINT | syscall.PARMRK | syscall.ISTRIP | syscall.INLCR | syscall.IGNCR | syscall.ICRNL | syscall.IXON
+	raw.Oflag &^= syscall.OPOST
+	raw.Lflag &^= syscall.ECHO | syscall.ECHONL | syscall.ICANON | syscall.ISIG | syscall.IEXTEN
+	raw.Cflag &^= syscall.CSIZE | syscall.PARENB
+	raw.Cflag |= syscall.CS8
+	raw.Cc[syscall.VMIN] = 1
+	raw.Cc[syscall.VTIME] = 0
+	if err := ioctl(fd, syscall.TCSETS, &raw); err != nil {
+		return nil, err
+	}
+	return &state, nil
+}
+
+// DisableEcho turns off echo of the characters typed, keeping the
+// terminal line-buffered. It returns the previous state.
+func DisableEcho(fd uintptr) (*State, error) {
+	var state State
+	if err := ioctl(fd, syscall.TCGETS, &state.termios); err != nil {
+		return nil, err
+	}
+
+	noecho := state.termios
+	noecho.Lflag &^= syscall.ECHO
+	noecho.Lflag |= syscall.ICANON | syscall.ISIG
+	if err := ioctl(fd, syscall.TCSETS, &noecho); err != nil {
+		return nil, err
+	}
+	return &state, nil
+}
+
+// Restore brings the terminal back to a previous state.
+func Restore(fd uintptr, state *State) error {
+	return ioctl(fd, syscall.TCSETS, &state.termios)
+}
+
+// Size returns the visible dimensions of the terminal fd refers to.
+func Size(fd uintptr) (width, height int, err error) {
+	var ws winsize
+	_, _, errno := syscall.Syscall(syscall.SYS_IOCTL, fd, syscall.TIOCGWINSZ, uintptr(unsafe.Pointer(&ws)))
+	if errno != 0 {
+		return 0, 0, errno
+	}
+	return int(ws.cols), int(ws.rows), nil
+}
@@ -0,0 +1,39 @@
+//go:build !linux
+
+package term
+
+import (
+	"errors"
+)
+
+var errUnsupported = errors.New("terminal is not supported on this platform")
+
+// State is a terminal state to restore.
+type State struct{}
+
+// IsTerminal reports whether fd refers to a terminal.
+func IsTerminal(fd uintptr) bool {
+	return false
+}
+
+// Size returns the visible dimensions of the terminal fd refers to.
+func Size(fd uintptr) (width, height int, err error) {
+	return 0, 0, errUnsupported
+}
+
+// MakeRaw puts the terminal into raw mode and returns the previous
+// state.
+func MakeRaw(fd uintptr) (*State, error) {
+	return nil, errUnsupported
+}
+
+// DisableEcho turns off echo of the characters typed and returns
+// the previous state.
+func DisableEcho(fd uintptr) (*State, error) {
+	return nil, errUnsupported
+}
+
+// Restore brings the terminal back to a previous state.
+func Restore(fd uintptr, state *State) error {
+	return errUnsupported
+}
@@ -0,0 +1,202 @@
+package cli
+
+import (
+	"strings"
+)
+
+// nameColumn is the minimal width of the name column in definition
+// lists, the one `go help` uses.
+const nameColumn = 11
+
+// layout is a structured help page renderer.
+//
+// A page is a sequence of blocks, each of them separated from the
+// next one by exactly one blank line. Blocks never add blank lines
+// on their own, so the ones the author puts into Help or Text are
+// kept.
+type layout struct {
+	blocks []block
+
+	// width is the number of columns text gets wrapped to.
+	width int
+
+	// theme styles headings.
+	theme *Theme
+}
+
+// newLayout returns a page fitting the terminal.
+func newLayout(theme *Theme) *layout {
+	return &layout{width: terminalWidth(), theme: theme}
+}
+
+// block is a piece of a help page.
+type block interface {
+	lines(width int) []string
+}
+
+// paragraph is a piece of text, reflowed to the page width.
+type paragraph string
+
+func (p paragraph) lines(width int) []string {
+	return reflow(string(p), width)
+}
+
+// heading is a single styled line of text.
+type heading struct {
+	text  string
+	style string
+}
+
+func (h heading) lines(width int) []string {
+	return []string{paint(h.style, h.text)}
+}
+
+// indented is a piece of text shifted by a single tab.
+type indented string
+
+func (t indented) lines(width int) []string {
+	lines := strings.Split(string(t), "\n")
+	for i, line := range lines {
+		if line != "" {
+			lines[i] = "\t" + line
+		}
+	}
+	return lines
+}
+
+// definition is a term followed by its description, e.g. a command
+// name and its brief.
+type definition struct {
+	term string
+	desc string
+}
+
+// definitionList is an indented list of definitions.
+//
+// Columned lists put descriptions next to their terms, padded to the
+// name column. Otherwise descriptions hang under the term, with an
+// extra level of indentation. Spaced lists separate definitions with
+// a blank line.
+type definitionList struct {
+	items    []definition
+	columned bool
+	spaced   bool
+
+	// termStyle is the style terms get painted with.
+	termStyle string
+
+	// aligned are the definitions the name column fits, those of
+	// all lists of a section, or just the items if nil.
+	aligned []definition
+}
+
+func (l definitionList) lines(width int) []string {
+	var lines []string
+	nameWidth := l.nameWidth(width)
+	for i, item := range l.items {
+		if l.spaced && i > 0 {
+			lines = append(lines, "")
+		}
+
+		if l.columned && item.desc == "" {
+			lines = append(lines, "\t"+paint(l.termStyle, item.term))
+			continue
+		}
+		if l.columned {
+			column := strings.Repeat(" ", nameWidth+1)
+			desc := reflow(item.desc, width-tabWidth-len(column))
+
+			term := "\t" + column
+			if n := visibleWidth(item.term); n > nameWidth {
+				lines = append(lines, "\t"+paint(l.termStyle, item.term))
+			} else {
+				term = "\t" + paint(l.termStyle, item.term) + column[n:]
+			}
+			for j, line := range desc {
+				if j > 0 {
+					term = "\t" + column
+				}
+				lines = append(lines, term+line)
+			}
+			continue
+		}
+
+		lines = append(lines, "\t"+paint(l.termStyle, item.term))
+		if item.desc != "" {
+			for _, line := range reflow(item.desc, width-2*tabWidth) {
+				lines = append(lines, "\t\t"+line)
+			}
+		}
+	}
+	return lines
+}
+
+// nameWidth returns the width of the name column.
+func (l definitionList) nameWidth(width int) int {
+	limit := (width - tabWidth) / 3
+	column := nameColumn
+	aligned := l.aligned
+	if aligned == nil {
+		aligned = l.items
+	}
+	for _, item := range aligned {
+		if n := visibleWidth(item.term); n > column && n <= limit {
+			column = n
+		}
+	}
+	return column
+}
+
+// add appends a block unless it's empty.
+func (l *layout) add(b block) {
+	switch b := b.(type) {
+	case paragraph:
+		if strings.TrimSpace(string(b)) == "" {
+			return
+		}
+		b = paragraph(strings.Trim(string(b), "\n"))
+		l.blocks = append(l.blocks, b)
+	case definitionList:
+		if len(b.items) == 0 {
+			return
+		}
+		l.blocks = append(l.blocks, b)
+	default:
+		l.blocks = append(l.blocks, b)
+	}
+}
+
+// heading appends a styled line of text.
+func (l *layout) heading(text string) {
+	l.add(heading{text, l.theme.Heading})
+}
+
+// section appends a heading followed by its blocks. Nothing gets
+// appended if there are no blocks.
+func (l *layout) section(title string, blocks ...block) {
+	body := layout{width: l.width, theme: l.theme}
+	for _, b := range blocks {
+		body.add(b)
+	}
+	if len(body.blocks) == 0 {
+		return
+	}
+
+	l.heading(title)
+	l.blocks = append(l.blocks, body.blocks...)
+}
+
+// String renders the page. Output always ends with a single newline.
+func (l *layout) String() string {
+	var b strings.Builder
+	for i, each := range l.blocks {
+		if i > 0 {
+			b.WriteString("\n")
+		}
+		for _, line := range each.lines(l.width) {
+			b.WriteString(strings.TrimRight(line, " \t"))
+			b.WriteString("\n")
+		}
+	}
+	return b.String()
+}
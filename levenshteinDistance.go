@@ -2,40 +2,110 @@ package cli
 
 import (
 	"strings"
+	"unicode"
 )
 
-// levenshteinDistance compares two strings and returns the levenshtein distance between them.
-func levenshteinDistance(s, t string, ignoreCase bool) int {
-	if ignoreCase {
-		s = strings.ToLower(s)
-		t = strings.ToLower(t)
-	}
-	d := make([][]int, len(s)+1)
-	for i := range d {
-		d[i] = make([]int, len(t)+1)
-	}
+// Levenshtein returns the number of rune insertions, deletions and
+// substitutions needed to turn s into t, ignoring case.
+func Levenshtein(s, t string) float64 {
+	return editDistance(s, t, false, unitCost)
+}
+
+// DamerauLevenshtein is Levenshtein counting a transposition of two
+// adjacent runes as a single edit, e.g. "lsit" is one edit away from
+// "list".
+func DamerauLevenshtein(s, t string) float64 {
+	return editDistance(s, t, true, unitCost)
+}
+
+// KeyboardDistance is DamerauLevenshtein with substitutions of keys
+// adjacent on a QWERTY keyboard costing half an edit, since those are
+// the most likely typos.
+func KeyboardDistance(s, t string) float64 {
+	return editDistance(s, t, true, func(a, b rune) float64 {
+		if keysAdjacent(a, b) {
+			return 0.5
+		}
+		return 1
+	})
+}
+
+func unitCost(a, b rune) float64 {
+	return 1
+}
+
+// editDistance compares two strings rune by rune, ignoring case, and
+// returns the optimal string alignment distance between them.
+func editDistance(s, t string, transpositions bool, substitution func(a, b rune) float64) float64 {
+	a := []rune(strings.ToLower(s))
+	b := []rune(strings.ToLower(t))
+
+	d := make([][]float64, len(a)+1)
 	for i := range d {
-		d[i][0] = i
+		d[i] = make([]float64, len(b)+1)
+		d[i][0] = float64(i)
 	}
 	for j := range d[0] {
-		d[0][j] = j
+		d[0][j] = float64(j)
 	}
-	for j := 1; j <= len(t); j++ {
-		for i := 1; i <= len(s); i++ {
-			if s[i-1] == t[j-1] {
+	for i := 1; i <= len(a); i++ {
+		for j := 1; j <= len(b); j++ {
+			if a[i-1] == b[j-1] {
 				d[i][j] = d[i-1][j-1]
-			} else {
-				min := d[i-1][j]
-				if d[i][j-1] < min {
-					min = d[i][j-1]
-				}
-				if d[i-1][j-1] < min {
-					min = d[i-1][j-1]
+				continue
+			}
+
+			min := d[i-1][j] + 1
+			if d[i][j-1]+1 < min {
+				min = d[i][j-1] + 1
+			}
+			if cost := d[i-1][j-1] + substitution(a[i-1], b[j-1]); cost < min {
+				min = cost
+			}
+			if transpositions && i > 1 && j > 1 && a[i-1] == b[j-2] && a[i-2] == b[j-1] {
+				if cost := d[i-2][j-2] + 1; cost < min {
+					min = cost
 				}
-				d[i][j] = min + 1
 			}
+			d[i][j] = min
 		}
+	}
+	return d[len(a)][len(b)]
+}
+
+// keyboardRows is the QWERTY layout, with every row shifted relative
+// to the one above it the way it is on a real keyboard.
+var keyboardRows = []struct {
+	keys   string
+	offset float64
+}{
+	{"1234567890-=", 0},
+	{"qwertyuiop[]", 0.5},
+	{"asdfghjkl;'", 0.75},
+	{"zxcvbnm,./", 1.25},
+}
+
+// keysAdjacent reports whether two keys are next to each other.
+func keysAdjacent(a, b rune) bool {
+	ax, ay, ok := keyPosition(a)
+	if !ok {
+		return false
+	}
+	bx, by, ok := keyPosition(b)
+	if !ok || (ax == bx && ay == by) {
+		return false
+	}
 
+	dx, dy := ax-bx, ay-by
+	return dx >= -1 && dx <= 1 && dy >= -1 && dy <= 1
+}
+
+func keyPosition(key rune) (x, y float64, ok bool) {
+	key = unicode.ToLower(key)
+	for row, each := range keyboardRows {
+		if col := strings.IndexRune(each.keys, key); col >= 0 {
+			return float64(col) + each.offset, float64(row), true
+		}
 	}
-	return d[len(s)][len(t)]
+	return 0, 0, false
 }
@@ -0,0 +1,174 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode"
+)
+
+// Standard flags Args.Logger reads the level from. Add them to
+// App.Flags; both are repeatable:
+//
+//	$ app sync -vv
+//	$ app sync --quiet
+var (
+	VerboseFlag = &Flag{
+		Name:  "verbose",
+		Short: "v",
+		Usage: "--verbose",
+		Help:  "Log more, repeat for even more: -vv.",
+		Value: switchValue{},
+	}
+	QuietFlag = &Flag{
+		Name:  "quiet",
+		Short: "q",
+		Usage: "--quiet",
+		Help:  "Log less, repeat for even less: -qq.",
+		Value: switchValue{},
+	}
+)
+
+// Log formats, accepted by App.LogFormat.
+const (
+	LogText = "text"
+	LogJSON = "json"
+)
+
+// logEnv returns the name of the environment variable setting the log
+// level, e.g. MYAPP_LOG for myapp.
+func (a *App) logEnv() string {
+	return strings.Map(func(r rune) rune {
+		if r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r)) {
+			return unicode.ToUpper(r)
+		}
+		return '_'
+	}, a.Name) + "_LOG"
+}
+
+// logLevel returns the level set by the environment variable, warnings
+// by default, adjusted by -v and -q: every -v logs one level more.
+func (c *Args) logLevel() slog.Level {
+	level := slog.LevelWarn
+	if env := os.Getenv(c.app.logEnv()); env != "" {
+		if err := level.UnmarshalText([]byte(env)); err != nil {
+			level = slog.LevelWarn
+		}
+	}
+	return level - slog.Level(4*(c.Count(VerboseFlag.Name)-c.Count(QuietFlag.Name)))
+}
+
+// Logger returns a logger writing to Stderr. Records are prefixed
+// with the application name, the way errors are, or written as JSON
+// if App.LogFormat asks for that.
+//
+// Warnings and errors are logged by default. Every -v logs one level
+// more, every -q one level less. MYAPP_LOG=debug, named after the
+// application, sets the default level.
+//
+// To make libraries log the same way, set it as the default:
+//
+//	slog.SetDefault(args.Logger())
+func (c *Args) Logger() *slog.Logger {
+	if c.logger != nil {
+		return c.logger
+	}
+
+	level := c.logLevel()
+	if c.app.LogFormat == LogJSON {
+		handler := slog.NewJSONHandler(Stderr, &slog.HandlerOptions{Level: level})
+		c.logger = slog.New(handler).With("app", c.app.Name)
+	} else {
+		c.logger = slog.New(&logHandler{
+			app:   c.app,
+			level: level,
+			out:   Stderr,
+			mu:    new(sync.Mutex),
+		})
+	}
+	return c.logger
+}
+
+// logHandler writes records as `name: level: message key=value`.
+type logHandler struct {
+	app    *App
+	level  slog.Level
+	out    io.Writer
+	mu     *sync.Mutex
+	attrs  string
+	prefix string // group prefix of keys
+}
+
+func (h *logHandler) Enabled(_ context.Context, level slog.Level) bool {
+	return level >= h.level
+}
+
+func (h *logHandler) Handle(_ context.Context, r slog.Record) error {
+	level := strings.ToLower(r.Level.String())
+	if r.Level >= slog.LevelError {
+		level = paint(h.app.theme(h.out).Error, level+":")
+	} else {
+		level = strings.Replace(level, "warn", "warning", 1) + ":"
+	}
+
+	var b strings.Builder
+	b.WriteString(h.app.Name + ": " + level + " " + r.Message + h.attrs)
+	r.Attrs(func(attr slog.Attr) bool {
+		writeAttr(&b, h.prefix, attr)
+		return true
+	})
+	b.WriteString("\n")
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	_, err := io.WriteString(h.out, b.String())
+	return err
+}
+
+func (h *logHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	var b strings.Builder
+	for _, attr := range attrs {
+		writeAttr(&b, h.prefix, attr)
+	}
+	handler := *h
+	handler.attrs += b.String()
+	return &handler
+}
+
+func (h *logHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	handler := *h
+	handler.prefix += name + "."
+	return &handler
+}
+
+// writeAttr writes ` key=value`, quoting values with spaces, quotes
+// or equals signs.
+func writeAttr(b *strings.Builder, prefix string, attr slog.Attr) {
+	attr.Value = attr.Value.Resolve()
+	if attr.Equal(slog.Attr{}) {
+		return
+	}
+	if attr.Value.Kind() == slog.KindGroup {
+		if attr.Key != "" {
+			prefix += attr.Key + "."
+		}
+		for _, member := range attr.Value.Group() {
+			writeAttr(b, prefix, member)
+		}
+		return
+	}
+
+	value := attr.Value.String()
+	if value == "" || strings.ContainsAny(value, " \t\n\"=") {
+		value = strconv.Quote(value)
+	}
+	fmt.Fprintf(b, " %s%s=%s", prefix, attr.Key, value)
+}
@@ -0,0 +1,65 @@
+package cli
+
+import (
+	"encoding/json"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func TestArgs_Logger(t *testing.T) {
+	a := NewApp("my-app")
+	a.Color = ColorNever
+	a.Flags = []*Flag{VerboseFlag, QuietFlag}
+	a.Commands = []*Command{{
+		Name:       "sync",
+		Positional: true,
+		Handle: func(args *Args) int {
+			logger := args.Logger().With("remote", "origin")
+			logger.Debug("fetching", "refs", 2)
+			logger.Info("fetched", slog.Group("objects", "new", 3, "path", "a b"))
+			logger.WithGroup("push").Warn("rejected", "ref", "main")
+			logger.Error("failed")
+			return 0
+		},
+	}}
+	defer output.Reset()
+
+	check := func(arguments []string, expected ...string) {
+		output.Reset()
+		a.Exec(arguments)
+		if output.String() != strings.Join(expected, "") {
+			t.Errorf("unexpected output of %v: %q", arguments, output.String())
+			t.Logf("- expected: %q", strings.Join(expected, ""))
+		}
+	}
+
+	var (
+		debug = "my-app: debug: fetching remote=origin refs=2\n"
+		info  = "my-app: info: fetched remote=origin objects.new=3 objects.path=\"a b\"\n"
+		warn  = "my-app: warning: rejected remote=origin push.ref=main\n"
+		err   = "my-app: error: failed remote=origin\n"
+	)
+	check([]string{"sync"}, warn, err)
+	check([]string{"-v", "sync", "-v"}, debug, info, warn, err)
+	check([]string{"sync", "-vv", "-q"}, info, warn, err)
+	check([]string{"sync", "--quiet"}, err)
+	check([]string{"sync", "-v", "origin"}, info, warn, err)
+	check([]string{"sync", "-v=false", "--quiet=off"}, warn, err)
+	check([]string{"-v=true", "sync"}, info, warn, err)
+
+	t.Setenv("MY_APP_LOG", "info")
+	check([]string{"sync"}, info, warn, err)
+	check([]string{"sync", "-qq"}, err)
+
+	a.LogFormat = LogJSON
+	output.Reset()
+	a.Exec([]string{"sync", "-qq"})
+	var record map[string]interface{}
+	if e := json.Unmarshal(output.Bytes(), &record); e != nil {
+		t.Fatalf("invalid JSON record %q: %s", output.String(), e)
+	}
+	if record["app"] != "my-app" || record["msg"] != "failed" || record["level"] != "ERROR" {
+		t.Errorf("unexpected record: %v", record)
+	}
+}
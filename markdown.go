@@ -0,0 +1,83 @@
+package cli
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Inline markdown elements, in the order they get rendered.
+var markdownInline = []struct {
+	pattern *regexp.Regexp
+	replace string
+}{
+	{regexp.MustCompile("`([^`]+)`"), "$1"},
+	{regexp.MustCompile(`!\[([^\]]*)\]\([^)]*\)`), "$1"},
+	{regexp.MustCompile(`\[([^\]]+)\]\(([^)]+)\)`), "$1 <$2>"},
+	{regexp.MustCompile(`\*\*([^*]+)\*\*`), "$1"},
+	{regexp.MustCompile(`__([^_]+)__`), "$1"},
+	{regexp.MustCompile(`\*([^*\s][^*]*)\*`), "$1"},
+	{regexp.MustCompile(`\b_([^_\s][^_]*)_\b`), "$1"},
+}
+
+// markdownRule matches thematic breaks.
+var markdownRule = regexp.MustCompile(`^(\*\s*){3,}$|^(-\s*){3,}$|^(_\s*){3,}$`)
+
+// renderMarkdown turns markdown into plain text help gets made of.
+//
+// Headings become paragraphs of their own, code blocks and quotes are
+// indented, so they're kept as they are, and inline markup is dropped.
+// Links keep their URL next to the text.
+func renderMarkdown(src string) string {
+	var (
+		lines  []string
+		fenced bool
+	)
+	blank := func() {
+		if len(lines) > 0 && lines[len(lines)-1] != "" {
+			lines = append(lines, "")
+		}
+	}
+
+	for _, line := range strings.Split(strings.ReplaceAll(src, "\r\n", "\n"), "\n") {
+		trimmed := strings.TrimSpace(line)
+
+		if strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~") {
+			if !fenced {
+				blank()
+			}
+			fenced = !fenced
+			continue
+		}
+		if fenced {
+			lines = append(lines, "\t"+line)
+			continue
+		}
+
+		switch {
+		case trimmed == "":
+			blank()
+		case strings.HasPrefix(line, "    ") || strings.HasPrefix(line, "\t"):
+			lines = append(lines, "\t"+strings.TrimPrefix(strings.TrimPrefix(line, "    "), "\t"))
+		case strings.HasPrefix(trimmed, "#"):
+			blank()
+			lines = append(lines, renderInline(strings.TrimSpace(strings.TrimLeft(trimmed, "#"))), "")
+		case markdownRule.MatchString(trimmed):
+			blank()
+		case strings.HasPrefix(trimmed, ">"):
+			lines = append(lines, "\t"+renderInline(strings.TrimSpace(strings.TrimPrefix(trimmed, ">"))))
+		case strings.HasPrefix(trimmed, "+ "):
+			lines = append(lines, "- "+renderInline(trimmed[2:]))
+		default:
+			lines = append(lines, renderInline(trimmed))
+		}
+	}
+
+	return strings.Trim(strings.Join(lines, "\n"), "\n")
+}
+
+func renderInline(text string) string {
+	for _, element := range markdownInline {
+		text = element.pattern.ReplaceAllString(text, element.replace)
+	}
+	return text
+}
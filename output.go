@@ -0,0 +1,276 @@
+package cli
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"text/template"
+)
+
+// Output formats, accepted by the --output flag.
+const (
+	OutputTable    = "table"
+	OutputJSON     = "json"
+	OutputJSONL    = "jsonl"
+	OutputCSV      = "csv"
+	OutputTemplate = "template"
+)
+
+// OutputFlag is the standard --output flag Args.Output reads the format
+// from. Add it to App.Flags to let users pick the format of every
+// command:
+//
+//	$ app list -o json
+//	$ app list --output='template={{.Name}} is {{.Status}}'
+var OutputFlag = &Flag{
+	Name:  "output",
+	Short: "o",
+	Usage: "--output=FORMAT",
+	Help:  "Format the output: table, json, jsonl, csv or template=TEXT.",
+	Value: formatValue{},
+}
+
+// formatValue checks --output values while the arguments are parsed.
+// The value itself stays in Args.
+type formatValue struct{}
+
+func (formatValue) String() string { return "" }
+func (formatValue) Type() string   { return "format" }
+
+func (formatValue) Set(value string) error {
+	format, _, _ := strings.Cut(value, "=")
+	switch format {
+	case OutputTable, OutputJSON, OutputJSONL, OutputCSV, OutputTemplate:
+		return nil
+	}
+	return fmt.Errorf(`expected %s, %s, %s, %s or %s=TEXT`,
+		OutputTable, OutputJSON, OutputJSONL, OutputCSV, OutputTemplate)
+}
+
+// Output writes v to Stdout in the format the --output flag asks for,
+// an aligned table by default.
+//
+// V is a struct, a map with string keys or a slice of those. Columns
+// are named after exported struct fields, or their json tags, and map
+// keys. Templates are executed for every element of the slice.
+func (c *Args) Output(v interface{}) error {
+	format, text, _ := strings.Cut(c.String(OutputFlag.Name), "=")
+	switch format {
+	case "", OutputTable:
+		return writeTable(Stdout, v)
+	case OutputJSON:
+		data, err := json.MarshalIndent(v, "", "  ")
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintln(Stdout, string(data))
+		return err
+	case OutputJSONL:
+		return writeJSONL(Stdout, v)
+	case OutputCSV:
+		return writeCSV(Stdout, v)
+	case OutputTemplate:
+		return writeTemplate(Stdout, v, text)
+	}
+	return fmt.Errorf(`unknown output format %q, expected %s, %s, %s, %s or %s=TEXT`,
+		format, OutputTable, OutputJSON, OutputJSONL, OutputCSV, OutputTemplate)
+}
+
+// elements returns the elements of a slice, or v itself otherwise.
+func elements(v interface{}) []reflect.Value {
+	value := reflect.ValueOf(v)
+	switch value.Kind() {
+	case reflect.Invalid:
+		return nil
+	case reflect.Slice, reflect.Array:
+	default:
+		return []reflect.Value{value}
+	}
+
+	items := make([]reflect.Value, value.Len())
+	for i := range items {
+		items[i] = value.Index(i)
+	}
+	return items
+}
+
+// indirect dereferences pointers and interfaces.
+func indirect(v reflect.Value) reflect.Value {
+	for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return reflect.Value{}
+		}
+		v = v.Elem()
+	}
+	return v
+}
+
+// column is a struct field or a map key.
+type column struct {
+	name  string
+	field []int
+}
+
+// columnsOf returns the columns of the items: fields of the struct
+// type, or keys of all maps, sorted. Items must be all of the same
+// struct type, or all maps.
+func columnsOf(items []reflect.Value) ([]column, error) {
+	var columns []column
+	var first reflect.Type
+	keys := make(map[string]bool)
+	for _, item := range items {
+		item = indirect(item)
+		if !item.IsValid() {
+			continue
+		}
+		t := item.Type()
+		if first == nil {
+			first = t
+		}
+		if t != first && (t.Kind() != reflect.Map || first.Kind() != reflect.Map) {
+			return nil, fmt.Errorf(`can't output a mix of %s and %s`, first, t)
+		}
+
+		switch item.Kind() {
+		case reflect.Struct:
+			if columns != nil {
+				continue
+			}
+			columns = []column{}
+			for i := 0; i < t.NumField(); i++ {
+				field := t.Field(i)
+				name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+				if field.PkgPath != "" || name == "-" {
+					continue
+				}
+				if name == "" {
+					name = field.Name
+				}
+				columns = append(columns, column{name: name, field: field.Index})
+			}
+		case reflect.Map:
+			if item.Type().Key().Kind() != reflect.String {
+				return nil, fmt.Errorf(`can't output maps with %s keys`, item.Type().Key())
+			}
+			for _, key := range item.MapKeys() {
+				if !keys[key.String()] {
+					keys[key.String()] = true
+					columns = append(columns, column{name: key.String()})
+				}
+			}
+		default:
+			return nil, fmt.Errorf(`can't output %s, expected a struct, a map or a slice of those`, item.Type())
+		}
+	}
+	if len(keys) > 0 {
+		sort.Slice(columns, func(i, j int) bool { return columns[i].name < columns[j].name })
+	}
+	return columns, nil
+}
+
+// cell returns the value of the column in the item as a string.
+func (col column) cell(item reflect.Value) string {
+	item = indirect(item)
+	var value reflect.Value
+	switch item.Kind() {
+	case reflect.Struct:
+		value = item.FieldByIndex(col.field)
+	case reflect.Map:
+		value = item.MapIndex(reflect.ValueOf(col.name).Convert(item.Type().Key()))
+	}
+	if value = indirect(value); !value.IsValid() {
+		return ""
+	}
+	return fmt.Sprint(value.Interface())
+}
+
+func writeTable(w io.Writer, v interface{}) error {
+	items := elements(v)
+	columns, err := columnsOf(items)
+	if err != nil || len(columns) == 0 {
+		return err
+	}
+
+	var buf strings.Builder
+	table := tabwriter.NewWriter(&buf, 0, tabWidth, 2, ' ', 0)
+	row := make([]string, len(columns))
+	for i, col := range columns {
+		row[i] = strings.ToUpper(col.name)
+	}
+	fmt.Fprintln(table, strings.Join(row, "\t"))
+	for _, item := range items {
+		for i, col := range columns {
+			row[i] = col.cell(item)
+		}
+		fmt.Fprintln(table, strings.Join(row, "\t"))
+	}
+	table.Flush()
+
+	// empty cells at the end of a row leave padding behind
+	for _, line := range strings.SplitAfter(buf.String(), "\n") {
+		if line != "" {
+			if _, err := io.WriteString(w, strings.TrimRight(line, " \n")+"\n"); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func writeJSONL(w io.Writer, v interface{}) error {
+	encoder := json.NewEncoder(w)
+	for _, item := range elements(v) {
+		if err := encoder.Encode(item.Interface()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func writeCSV(w io.Writer, v interface{}) error {
+	items := elements(v)
+	columns, err := columnsOf(items)
+	if err != nil || len(columns) == 0 {
+		return err
+	}
+
+	writer := csv.NewWriter(w)
+	row := make([]string, len(columns))
+	for i, col := range columns {
+		row[i] = col.name
+	}
+	writer.Write(row)
+	for _, item := range items {
+		for i, col := range columns {
+			row[i] = col.cell(item)
+		}
+		writer.Write(row)
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+func writeTemplate(w io.Writer, v interface{}, text string) error {
+	if text == "" {
+		return fmt.Errorf(`no template given, expected %s=TEXT`, OutputTemplate)
+	}
+	tmpl, err := template.New(OutputFlag.Name).Parse(text)
+	if err != nil {
+		return err
+	}
+
+	for _, item := range elements(v) {
+		if err := tmpl.Execute(w, item.Interface()); err != nil {
+			return err
+		}
+		if !strings.HasSuffix(text, "\n") {
+			fmt.Fprintln(w)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,101 @@
+package cli
+
+import (
+	"strings"
+	"testing"
+)
+
+type service struct {
+	Name   string `json:"name"`
+	Status string `json:"status"`
+	Port   *int   `json:"port,omitempty"`
+	secret string
+	Hidden string `json:"-"`
+}
+
+func TestArgs_Output(t *testing.T) {
+	port := 8080
+	services := []service{
+		{Name: "web", Status: "running", Port: &port},
+		{Name: "database", Status: "stopped", secret: "x", Hidden: "y"},
+	}
+	defer output.Reset()
+
+	check := func(format string, v interface{}, expected string) {
+		output.Reset()
+		args := &Args{vars: map[string]string{"output": format}}
+		if err := args.Output(v); err != nil {
+			t.Errorf("%s output failed: %s", format, err)
+		}
+		if output.String() != expected {
+			t.Errorf("unexpected %s output: %q", format, output.String())
+			t.Logf("- expected: %q", expected)
+		}
+	}
+
+	check("", services, "NAME      STATUS   PORT\nweb       running  8080\ndatabase  stopped\n")
+	check("json", services[1], "{\n  \"name\": \"database\",\n  \"status\": \"stopped\"\n}\n")
+	check("jsonl", services, `{"name":"web","status":"running","port":8080}`+"\n"+`{"name":"database","status":"stopped"}`+"\n")
+	check("csv", services, "name,status,port\nweb,running,8080\ndatabase,stopped,\n")
+	check("template={{.Name}} is {{.Status}}", services, "web is running\ndatabase is stopped\n")
+	check("table", []map[string]interface{}{
+		{"name": "web", "replicas": 3},
+		{"name": "worker", "queue": "jobs, mail"},
+	}, "NAME    QUEUE       REPLICAS\nweb                 3\nworker  jobs, mail\n")
+	check("csv", map[string]string{"name": "a,b"}, "name\n\"a,b\"\n")
+
+	for _, format := range []string{"yaml", "template"} {
+		args := &Args{vars: map[string]string{"output": format}}
+		if err := args.Output(services); err == nil {
+			t.Errorf("%s output is accepted", format)
+		}
+	}
+	if err := (&Args{}).Output([]int{1}); err == nil {
+		t.Error("output of integers is accepted")
+	}
+	mixed := []interface{}{services[0], struct{ Name string }{"other"}}
+	if err := (&Args{}).Output(mixed); err == nil {
+		t.Error("output of mixed struct types is accepted")
+	}
+	if err := (&Args{}).Output([]interface{}{map[string]int{"a": 1}, services[0]}); err == nil {
+		t.Error("output of maps mixed with structs is accepted")
+	}
+}
+
+func TestArgs_OutputFlag(t *testing.T) {
+	ran := false
+	a := NewApp("cli")
+	a.Flags = []*Flag{OutputFlag}
+	a.Commands = []*Command{{
+		Name: "list",
+		Handle: func(args *Args) int {
+			ran = true
+			if err := args.Output([]service{{Name: "web", Status: "running"}}); err != nil {
+				return 1
+			}
+			return 0
+		},
+	}}
+	defer output.Reset()
+
+	for _, arguments := range [][]string{{"list", "-o", "jsonl"}, {"--output=jsonl", "list"}} {
+		output.Reset()
+		if exitcode := a.Exec(arguments); exitcode != 0 {
+			t.Errorf("%v finished with code %d", arguments, exitcode)
+		}
+		if expected := `{"name":"web","status":"running"}` + "\n"; output.String() != expected {
+			t.Errorf("unexpected output of %v: %q", arguments, output.String())
+		}
+	}
+
+	for _, arguments := range [][]string{{"list", "-o", "yaml"}, {"--output=yaml", "list"}} {
+		output.Reset()
+		ran = false
+		if exitcode := a.Exec(arguments); exitcode != 1 || ran {
+			t.Errorf("%v finished with code %d, ran the handler: %v", arguments, exitcode, ran)
+		}
+		if !strings.Contains(output.String(), `invalid value "yaml" for option --output`) {
+			t.Errorf("invalid format isn't reported: %q", output.String())
+		}
+	}
+}
@@ -0,0 +1,47 @@
+package cli
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+)
+
+// defaultPager is used if PAGER is not set.
+const defaultPager = "less -R"
+
+// pagingTerminal reports whether Stdout is a terminal, replaced in tests.
+var pagingTerminal = func() bool { return isTerminal(Stdout) }
+
+// page prints text just like println does, but pipes it through
+// the pager if it doesn't fit the terminal screen.
+//
+// Paging only happens if App.Pager is on and Stdout is a terminal.
+// PAGER is run by the shell, so it may have quoted arguments, pipes
+// and the like. If the pager can't be started, text gets printed as is.
+func (a *App) page(text string) {
+	height := terminalHeight()
+	if !a.Pager || !pagingTerminal() || height == 0 || strings.Count(text, "\n") < height {
+		a.println(text)
+		return
+	}
+
+	pager := os.Getenv("PAGER")
+	if strings.TrimSpace(pager) == "" {
+		pager = defaultPager
+	}
+
+	cmd := exec.Command("sh", "-c", pager)
+	if runtime.GOOS == "windows" {
+		cmd = exec.Command("cmd", "/C", pager)
+	}
+	cmd.Stdin = strings.NewReader(text + "\n")
+	cmd.Stdout = Stdout
+	cmd.Stderr = Stderr
+
+	var exitErr *exec.ExitError
+	if err := cmd.Run(); err != nil && !errors.As(err, &exitErr) {
+		a.println(text)
+	}
+}
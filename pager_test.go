@@ -0,0 +1,34 @@
+package cli
+
+import (
+	"runtime"
+	"testing"
+)
+
+func TestPage(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the fake pager needs sh")
+	}
+	defer func(saved func() bool) { pagingTerminal = saved }(pagingTerminal)
+	pagingTerminal = func() bool { return true }
+	t.Setenv("LINES", "2")
+	t.Setenv("PAGER", `sed 's/^/| /'`)
+	defer output.Reset()
+
+	check := func(text, expected string) {
+		t.Helper()
+		output.Reset()
+		a := NewApp("cli")
+		a.page(text)
+		if output.String() != expected {
+			t.Errorf("paged %q as %q, expected %q", text, output.String(), expected)
+		}
+	}
+
+	check("short", "short\n")
+	check("one\ntwo\nthree", "| one\n| two\n| three\n")
+
+	t.Setenv("PAGER", "")
+	t.Setenv("PATH", "")
+	check("one\ntwo\nthree", "one\ntwo\nthree\n")
+}
@@ -5,15 +5,54 @@ import (
 	"strings"
 )
 
+// unknownOptionError is returned in strict mode for options none
+// of the flags is defined for.
+type unknownOptionError struct {
+	name        string
+	suggestions []string
+}
+
+func (e *unknownOptionError) Error() string {
+	if len(e.suggestions) == 0 {
+		return fmt.Sprintf(`option -%s does not exist`, e.name)
+	}
+	return fmt.Sprintf(`option -%s does not exist, did you mean %s?`, e.name, strings.Join(e.suggestions, " or "))
+}
+
+// parsed holds the options and arguments parsed by parseArguments.
+type parsed struct {
+	vars      map[string]string
+	counts    map[string]int // occurrences of the options, -vv counts twice
+	arguments []string
+}
+
+// parseVariables parses options, which are all there is to argv in
+// strict mode.
 func parseVariables(beStrict bool, flags []*Flag, argv []string) (map[string]string, error) {
-	vars := make(map[string]string, 0)
+	p, err := parseArguments(beStrict, false, flags, argv)
+	if err != nil {
+		return nil, err
+	}
+	return p.vars, nil
+}
+
+// parseArguments parses options and positional arguments: those
+// preceding the options and those following boolean ones, which never
+// take arguments. Other options take the arguments up to the next
+// option as their value. A lone `-` is an argument, and everything
+// after `--` is. In strict mode, positional arguments are errors
+// unless positional is true.
+func parseArguments(beStrict, positional bool, flags []*Flag, argv []string) (*parsed, error) {
+	p := &parsed{vars: make(map[string]string), counts: make(map[string]int)}
 	for i := 0; i < len(argv); i++ {
 		argument := argv[i]
 
-		if !strings.HasPrefix(argument, "-") {
-			if beStrict {
-				return nil, fmt.Errorf(`no option name before argument %s`, argument)
-			}
+		if argument == "--" {
+			p.arguments = append(p.arguments, argv[i+1:]...)
+			break
+		}
+		if !isOption(argument) {
+			p.arguments = append(p.arguments, argument)
 			continue
 		}
 
@@ -23,16 +62,16 @@ func parseVariables(beStrict bool, flags []*Flag, argv []string) (map[string]str
 
 		name := parts[0]
 
-		var flag *Flag
-		for _, f := range flags {
-			if f.Name == name || f.Short == name {
-				flag = f
-				break
+		flag := lookupFlag(flags, name)
+		n := 1
+		if flag == nil {
+			if flag = repeatedShort(flags, name); flag != nil {
+				n = len(name) / len(flag.Short)
 			}
 		}
 		if flag == nil {
 			if beStrict {
-				return nil, fmt.Errorf(`option -%s does not exist`, name)
+				return nil, &unknownOptionError{name: name}
 			}
 			flag = &Flag{Name: name}
 		}
@@ -42,21 +81,84 @@ func parseVariables(beStrict bool, flags []*Flag, argv []string) (map[string]str
 			value = parts[1]
 		}
 
-		for i+1 < len(argv) {
-			if strings.HasPrefix(argv[i+1], "-") {
+		for i+1 < len(argv) && !isBoolValue(flag) {
+			if isOption(argv[i+1]) || argv[i+1] == "--" {
 				break
 			}
 			value += " " + argv[i+1]
 			i++
 		}
 
-		if len(parts) == 1 && len(value) == 0 {
-			vars[flag.Name] = ""
-			continue
+		value = strings.TrimLeft(value, " ")
+		if err := checkChoice(flag, value); err != nil {
+			return nil, err
+		}
+		if flag.Value != nil {
+			if err := setValue(flag, value, len(parts) > 1); err != nil {
+				return nil, err
+			}
 		}
 
-		vars[flag.Name] = strings.TrimLeft(value, " ")
+		// explicitly false switches, e.g. -v=false, don't count
+		if b, err := parseBool(value); isBoolValue(flag) && len(parts) > 1 && err == nil && !b {
+			n = 0
+		}
+		p.vars[flag.Name] = value
+		p.counts[flag.Name] += n
 	}
 
-	return vars, nil
+	if beStrict && !positional && len(p.arguments) > 0 {
+		return nil, fmt.Errorf(`no option name before argument %s`, Join(p.arguments[:1]))
+	}
+	return p, nil
+}
+
+// isOption reports whether the argument is an option, e.g. -v or
+// --all, rather than a lone dash or the end of options.
+func isOption(argument string) bool {
+	return strings.HasPrefix(argument, "-") && strings.Trim(argument, "-") != ""
+}
+
+// isBoolValue reports whether the flag carries a boolean Value, or one
+// implementing IsBoolFlag like those of the flag package, which never
+// takes the arguments following it.
+func isBoolValue(flag *Flag) bool {
+	if flag.Value == nil {
+		return false
+	}
+	if boolean, ok := flag.Value.(interface{ IsBoolFlag() bool }); ok && boolean.IsBoolFlag() {
+		return true
+	}
+	return flag.Value.Type() == "bool"
+}
+
+// setValue calls Set of the flag's Value. Boolean values given without
+// an explicit value are set to true.
+func setValue(flag *Flag, value string, explicit bool) error {
+	if value == "" && !explicit && flag.Value.Type() == "bool" {
+		value = "true"
+	}
+	if err := flag.Value.Set(value); err != nil {
+		return fmt.Errorf(`invalid value %q for option --%s: %s`, value, flag.Name, err)
+	}
+	return nil
+}
+
+// checkChoice reports an error if the flag has choices and the value
+// is none of them.
+func checkChoice(flag *Flag, value string) error {
+	if len(flag.Choices) == 0 {
+		return nil
+	}
+	for _, choice := range flag.Choices {
+		if value == choice {
+			return nil
+		}
+	}
+
+	expected := strings.Join(flag.Choices, ", ")
+	if n := len(flag.Choices); n > 1 {
+		expected = strings.Join(flag.Choices[:n-1], ", ") + " or " + flag.Choices[n-1]
+	}
+	return fmt.Errorf(`invalid value %q for option --%s, expected %s`, value, flag.Name, expected)
 }
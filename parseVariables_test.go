@@ -1,7 +1,10 @@
 package cli
 
 import (
+	"errors"
 	"reflect"
+	"strconv"
+	"strings"
 	"testing"
 )
 
@@ -111,3 +114,96 @@ func TestContext(t *testing.T) {
 		{Name: "filter"},
 	}, []string{"--notexist"})
 }
+
+func TestContext_Suggestions(t *testing.T) {
+	a := NewApp("cli")
+	flags := []*Flag{
+		{Name: "separator", Short: "s"},
+		{Name: "force", Short: "f"},
+		{Name: "filter", Short: "fl"},
+	}
+
+	check := func(argument, expected string) {
+		_, err := newContext(a, flags, []string{argument}, false)
+		if err == nil || err.Error() != expected {
+			t.Errorf(`unexpected error for %s: %v`, argument, err)
+			t.Logf("- expected: %s", expected)
+		}
+	}
+
+	check("--seperator", "option -seperator does not exist, did you mean --separator?")
+	check("--fil", "option -fil does not exist, did you mean -fl or -f or --filter?")
+	check("-S", "option -S does not exist, did you mean -s or --separator?")
+	check("-x", "option -x does not exist")
+}
+
+// byteSize is a Value accepting sizes like 10k or 5M.
+type byteSize int64
+
+func (s *byteSize) String() string { return strconv.FormatInt(int64(*s), 10) }
+func (s *byteSize) Type() string   { return "size" }
+
+func (s *byteSize) Set(value string) error {
+	multiplier := int64(1)
+	switch {
+	case strings.HasSuffix(value, "k"):
+		multiplier = 1 << 10
+	case strings.HasSuffix(value, "M"):
+		multiplier = 1 << 20
+	}
+	n, err := strconv.ParseInt(strings.TrimRight(value, "kM"), 10, 64)
+	if err != nil {
+		return errors.New("expected a number of bytes, e.g. 10k")
+	}
+	*s = byteSize(n * multiplier)
+	return nil
+}
+
+// toggle is a boolean Value counting how many times it was set.
+type toggle int
+
+func (t *toggle) String() string { return strconv.Itoa(int(*t)) }
+func (t *toggle) Type() string   { return "bool" }
+
+func (t *toggle) Set(value string) error {
+	on, err := strconv.ParseBool(value)
+	if on {
+		*t++
+	}
+	return err
+}
+
+func TestContext_Value(t *testing.T) {
+	var (
+		size  byteSize
+		debug toggle
+	)
+	flags := []*Flag{
+		{Name: "size", Value: &size},
+		{Name: "debug", Short: "d", Value: &debug},
+	}
+
+	vars, err := parseVariables(false, flags, []string{"--size", "10k", "-d", "file.txt", "--debug=true"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if size != 10<<10 || debug != 2 {
+		t.Errorf("values are set to %s and %s, expected 10240 and 2", size.String(), debug.String())
+	}
+	if vars["size"] != "10k" {
+		t.Errorf("variable is %q, expected 10k", vars["size"])
+	}
+
+	_, err = parseVariables(false, flags, []string{"--size=lots"})
+	expected := `invalid value "lots" for option --size: expected a number of bytes, e.g. 10k`
+	if err == nil || err.Error() != expected {
+		t.Errorf("unexpected error %v, expected %s", err, expected)
+	}
+
+	if usage := flagUsage(flags[0], false); usage != "--size=size" {
+		t.Errorf("unexpected usage %q", usage)
+	}
+	if usage := flagUsage(flags[1], false); usage != "-d, --debug" {
+		t.Errorf("unexpected usage %q", usage)
+	}
+}
@@ -0,0 +1,123 @@
+package cli
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+// Environment variables describing the app to its plugins.
+const (
+	envPluginApp        = "CLI_APP"
+	envPluginVersion    = "CLI_APP_VERSION"
+	envPluginExecutable = "CLI_APP_EXECUTABLE"
+	envPluginColor      = "CLI_COLOR"
+)
+
+// pluginPrefix is the prefix of plugin executable names, e.g. `go-`.
+func (a *App) pluginPrefix() string {
+	return a.Name + "-"
+}
+
+// pluginDirs returns the directories plugins are looked up in.
+//
+// Relative directories in PATH are ignored, so plugins don't get
+// picked up from the current directory by accident.
+func (a *App) pluginDirs() []string {
+	var dirs []string
+	if a.PluginDir != "" {
+		if dir, err := filepath.Abs(a.PluginDir); err == nil {
+			dirs = append(dirs, dir)
+		}
+	}
+	for _, dir := range filepath.SplitList(os.Getenv("PATH")) {
+		if filepath.IsAbs(dir) {
+			dirs = append(dirs, dir)
+		}
+	}
+	return dirs
+}
+
+// lookPlugin returns the path to the executable of a plugin command.
+func (a *App) lookPlugin(name string) (string, bool) {
+	if !a.Plugins || name == "" || strings.ContainsAny(name, `/\`) {
+		return "", false
+	}
+
+	for _, dir := range a.pluginDirs() {
+		path, err := exec.LookPath(filepath.Join(dir, a.pluginPrefix()+name))
+		if err == nil {
+			return path, true
+		}
+	}
+	return "", false
+}
+
+// plugins returns paths to the plugin commands available by their
+// names, except for those shadowed by commands of the app itself.
+func (a *App) plugins() map[string]string {
+	plugins := make(map[string]string)
+	if !a.Plugins {
+		return plugins
+	}
+
+	for _, dir := range a.pluginDirs() {
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			continue
+		}
+
+		for _, entry := range entries {
+			name := entry.Name()
+			if !strings.HasPrefix(name, a.pluginPrefix()) || entry.IsDir() {
+				continue
+			}
+			if runtime.GOOS == "windows" {
+				name = strings.TrimSuffix(name, filepath.Ext(name))
+			}
+
+			name = strings.TrimPrefix(name, a.pluginPrefix())
+			if _, ok := plugins[name]; ok || a.commandByName(name) != nil {
+				continue
+			}
+			if path, ok := a.lookPlugin(name); ok {
+				plugins[name] = path
+			}
+		}
+	}
+	return plugins
+}
+
+// runPlugin executes a plugin with the arguments given and returns
+// its exit code.
+func (a *App) runPlugin(path string, arguments []string) int {
+	cmd := exec.Command(path, arguments...)
+	cmd.Stdin = Stdin
+	cmd.Stdout = Stdout
+	cmd.Stderr = Stderr
+
+	cmd.Env = append(os.Environ(),
+		envPluginApp+"="+a.Name,
+		envPluginVersion+"="+a.Version,
+	)
+	if executable, err := os.Executable(); err == nil {
+		cmd.Env = append(cmd.Env, envPluginExecutable+"="+executable)
+	}
+	if a.Color != "" {
+		cmd.Env = append(cmd.Env, envPluginColor+"="+a.Color)
+	}
+
+	err := cmd.Run()
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		return exitErr.ExitCode()
+	}
+	if err != nil {
+		a.printerr(err)
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,55 @@
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func TestRun_Plugin(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("plugin scripts need a unix shell")
+	}
+
+	dir := t.TempDir()
+	script := "#!/bin/sh\necho \"$CLI_APP $CLI_APP_VERSION: $*\"\nexit 3\n"
+	if err := os.WriteFile(filepath.Join(dir, "cli-hello"), []byte(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "cli-notes.txt"), nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	a := NewApp("cli")
+	a.Version = "5.0"
+	a.AddCommand(&Command{Name: "open", Brief: "opens smth"})
+	a.PluginDir = dir
+	defer setArguments()
+	defer output.Reset()
+
+	if _, ok := a.lookPlugin("hello"); ok {
+		t.Error("plugins are looked up without being turned on")
+	}
+
+	a.Plugins = true
+	setArguments("hello", "big", "world")
+	if exitcode := a.Run(); exitcode != 3 {
+		t.Errorf("finished with code %d, expected 3", exitcode)
+	}
+	if output.String() != "cli 5.0: big world\n" {
+		t.Errorf("unexpected plugin output: %q", output.String())
+	}
+
+	output.Reset()
+	setArguments("help")
+	a.Run()
+	expected := "The plugin commands are:\n\n\thello\n"
+	if !strings.Contains(output.String(), expected) {
+		t.Errorf("plugin isn't listed in help: %q", output.String())
+	}
+	if strings.Contains(output.String(), "notes") {
+		t.Errorf("non-executable file is listed in help: %q", output.String())
+	}
+}
@@ -0,0 +1,55 @@
+package cli
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/ccpaging/cli/progress"
+)
+
+// Context returns a context of the command call, done when the user
+// interrupts the command or its handler returns.
+//
+// Until the first call, interrupts terminate the application right
+// away, as usual; afterwards the handler is responsible for stopping.
+func (c *Args) Context() context.Context {
+	if c.ctx == nil {
+		c.ctx, c.cancel = signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	}
+	return c.ctx
+}
+
+// Progress returns a display of progress bars and spinners on Stderr,
+// stopped once the handler returns, or along with the Context if it's
+// been called before.
+//
+// Progress doesn't touch interrupts, they keep terminating the
+// application unless the handler calls Context.
+//
+//	bar := args.Progress().Bar("download", size)
+//	for ... {
+//		bar.Add(n)
+//	}
+func (c *Args) Progress() *progress.Display {
+	if c.display == nil {
+		ctx := c.ctx
+		if ctx == nil {
+			ctx = context.Background()
+		}
+		c.display = progress.New(ctx, Stderr)
+	}
+	return c.display
+}
+
+// release stops the progress display and the context once the handler
+// returns.
+func (c *Args) release() {
+	if c.display != nil {
+		c.display.Stop()
+	}
+	if c.cancel != nil {
+		c.cancel()
+	}
+}
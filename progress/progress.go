@@ -0,0 +1,272 @@
+// Package progress displays progress bars and spinners of long
+// operations.
+//
+// On a terminal, bars and spinners are redrawn in place several times
+// a second. Otherwise their state is printed as plain lines every few
+// seconds, so logs of scripts and CI jobs stay readable.
+package progress
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ccpaging/cli/internal/term"
+)
+
+// Intervals between updates.
+const (
+	TerminalInterval = 100 * time.Millisecond
+	PlainInterval    = 5 * time.Second
+)
+
+const barWidth = 30
+
+var spinnerFrames = []string{"|", "/", "-", `\`}
+
+// Display draws bars and spinners on Out until it's stopped or its
+// context is done. It's safe to update bars and spinners from many
+// goroutines.
+type Display struct {
+	out         io.Writer
+	interactive bool
+	interval    time.Duration
+
+	mu      sync.Mutex
+	items   []item
+	drawn   int // lines drawn by the last interactive update
+	frame   int
+	stopped bool
+	done    chan struct{}
+	wg      sync.WaitGroup
+}
+
+// item is either a bar or a spinner.
+type item interface {
+	// line returns the current state; plain lines are for non
+	// terminals.
+	line(frame int, plain bool) string
+
+	// changed reports whether the state changed since the last call.
+	changed() bool
+}
+
+// New starts a display on out, redrawn in place if out is a terminal.
+// It stops when ctx is done.
+func New(ctx context.Context, out io.Writer) *Display {
+	f, ok := out.(*os.File)
+	if ok && term.IsTerminal(f.Fd()) {
+		return newDisplay(ctx, out, true, TerminalInterval)
+	}
+	return newDisplay(ctx, out, false, PlainInterval)
+}
+
+func newDisplay(ctx context.Context, out io.Writer, interactive bool, interval time.Duration) *Display {
+	d := &Display{
+		out:         out,
+		interactive: interactive,
+		interval:    interval,
+		done:        make(chan struct{}),
+	}
+	d.wg.Add(1)
+	go d.run(ctx)
+	return d
+}
+
+func (d *Display) run(ctx context.Context) {
+	defer d.wg.Done()
+	ticker := time.NewTicker(d.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			d.mu.Lock()
+			d.frame++
+			d.update()
+			d.mu.Unlock()
+		case <-ctx.Done():
+			d.finish()
+			return
+		case <-d.done:
+			return
+		}
+	}
+}
+
+// Stop draws the final state of bars and spinners and stops the
+// display. It's safe to call it more than once.
+func (d *Display) Stop() {
+	if d.finish() {
+		close(d.done)
+	}
+	d.wg.Wait()
+}
+
+// finish draws the final state, unless it's been drawn already.
+func (d *Display) finish() bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.stopped {
+		return false
+	}
+	d.update()
+	d.stopped = true
+	return true
+}
+
+// update draws the items. Plain displays only print items changed.
+func (d *Display) update() {
+	if d.stopped {
+		return
+	}
+
+	if !d.interactive {
+		for _, it := range d.items {
+			if it.changed() {
+				fmt.Fprintln(d.out, it.line(d.frame, true))
+			}
+		}
+		return
+	}
+
+	var b strings.Builder
+	if d.drawn > 0 {
+		fmt.Fprintf(&b, "\x1b[%dA", d.drawn)
+	}
+	for _, it := range d.items {
+		b.WriteString("\r\x1b[K" + it.line(d.frame, false) + "\n")
+	}
+	d.drawn = len(d.items)
+	io.WriteString(d.out, b.String())
+}
+
+func (d *Display) add(it item) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.items = append(d.items, it)
+	d.update()
+}
+
+// Bar adds a progress bar of an operation with total steps.
+func (d *Display) Bar(label string, total int64) *Bar {
+	b := &Bar{display: d, label: label, total: total, reported: -1}
+	d.add(b)
+	return b
+}
+
+// Spinner adds a spinner of an operation of unknown length.
+func (d *Display) Spinner(label string) *Spinner {
+	s := &Spinner{display: d, label: label, dirty: true}
+	d.add(s)
+	return s
+}
+
+// Bar is a progress bar, see Display.Bar.
+type Bar struct {
+	display  *Display
+	label    string
+	total    int64
+	current  int64
+	reported int64
+}
+
+// Add advances the bar by n steps.
+func (b *Bar) Add(n int64) {
+	b.display.mu.Lock()
+	b.set(b.current + n)
+	b.display.mu.Unlock()
+}
+
+// Set moves the bar to step n.
+func (b *Bar) Set(n int64) {
+	b.display.mu.Lock()
+	b.set(n)
+	b.display.mu.Unlock()
+}
+
+// Done moves the bar to the end.
+func (b *Bar) Done() {
+	b.Set(b.total)
+}
+
+func (b *Bar) set(n int64) {
+	if n < 0 {
+		n = 0
+	}
+	if b.total > 0 && n > b.total {
+		n = b.total
+	}
+	b.current = n
+	if b.total > 0 && b.current == b.total {
+		b.display.update()
+	}
+}
+
+func (b *Bar) percent() int64 {
+	if b.total <= 0 {
+		return 0
+	}
+	return b.current * 100 / b.total
+}
+
+func (b *Bar) changed() bool {
+	changed := b.current != b.reported
+	b.reported = b.current
+	return changed
+}
+
+func (b *Bar) line(frame int, plain bool) string {
+	status := fmt.Sprintf("%3d%% %d/%d", b.percent(), b.current, b.total)
+	if plain {
+		return b.label + ": " + strings.TrimLeft(status, " ")
+	}
+
+	filled := int(b.percent() * barWidth / 100)
+	bar := strings.Repeat("=", filled)
+	if filled < barWidth {
+		bar += ">" + strings.Repeat(" ", barWidth-filled-1)
+	}
+	return b.label + " [" + bar + "] " + status
+}
+
+// Spinner is a spinner, see Display.Spinner.
+type Spinner struct {
+	display *Display
+	label   string
+	message string
+	stopped bool
+	dirty   bool
+}
+
+// Stop stops the spinner, displaying the message next to its label,
+// "done" if the message is empty.
+func (s *Spinner) Stop(message string) {
+	s.display.mu.Lock()
+	defer s.display.mu.Unlock()
+	if message == "" {
+		message = "done"
+	}
+	s.message, s.stopped, s.dirty = message, true, true
+	s.display.update()
+}
+
+func (s *Spinner) changed() bool {
+	changed := s.dirty
+	s.dirty = false
+	return changed
+}
+
+func (s *Spinner) line(frame int, plain bool) string {
+	switch {
+	case s.stopped:
+		return s.label + ": " + s.message
+	case plain:
+		return s.label + "..."
+	}
+	return spinnerFrames[frame%len(spinnerFrames)] + " " + s.label
+}
@@ -0,0 +1,102 @@
+package progress
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// buffer is a strings.Builder safe for concurrent use.
+type buffer struct {
+	mu sync.Mutex
+	b  strings.Builder
+}
+
+func (b *buffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.b.Write(p)
+}
+
+func (b *buffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.b.String()
+}
+
+func TestDisplay_Plain(t *testing.T) {
+	var out buffer
+	d := newDisplay(context.Background(), &out, false, time.Hour)
+	download := d.Bar("download", 200)
+	index := d.Spinner("indexing")
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			download.Add(25)
+		}()
+	}
+	wg.Wait()
+	index.Stop("")
+	d.Stop()
+	d.Stop()
+
+	expected := "download: 0% 0/200\n" +
+		"indexing...\n" +
+		"download: 50% 100/200\n" +
+		"indexing: done\n"
+	if out.String() != expected {
+		t.Errorf("unexpected output: %q", out.String())
+		t.Logf("- expected: %q", expected)
+	}
+}
+
+func TestDisplay_Terminal(t *testing.T) {
+	var out buffer
+	d := newDisplay(context.Background(), &out, true, time.Hour)
+	bar := d.Bar("copy", 4)
+	bar.Set(2)
+	d.Stop()
+
+	expected := "\r\x1b[Kcopy [>                             ]   0% 0/4\n" +
+		"\x1b[1A\r\x1b[Kcopy [===============>              ]  50% 2/4\n"
+	if out.String() != expected {
+		t.Errorf("unexpected output: %q", out.String())
+		t.Logf("- expected: %q", expected)
+	}
+}
+
+func TestDisplay_Cancel(t *testing.T) {
+	var out buffer
+	ctx, cancel := context.WithCancel(context.Background())
+	d := newDisplay(ctx, &out, false, time.Hour)
+	d.Spinner("waiting")
+	cancel()
+	d.wg.Wait()
+
+	d.Spinner("late")
+	d.Stop()
+	if expected := "waiting...\n"; out.String() != expected {
+		t.Errorf("unexpected output: %q", out.String())
+	}
+}
+
+func TestDisplay_Interval(t *testing.T) {
+	var out buffer
+	d := newDisplay(context.Background(), &out, false, time.Millisecond)
+	bar := d.Bar("upload", 10)
+	bar.Set(3)
+	time.Sleep(50 * time.Millisecond)
+	if !strings.Contains(out.String(), "upload: 30% 3/10\n") {
+		t.Errorf("the bar isn't updated periodically: %q", out.String())
+	}
+	bar.Done()
+	d.Stop()
+	if strings.Count(out.String(), "upload: 100% 10/10\n") != 1 {
+		t.Errorf("the finished bar isn't reported once: %q", out.String())
+	}
+}
@@ -0,0 +1,44 @@
+package cli
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestArgs_Progress(t *testing.T) {
+	var args *Args
+	a := NewApp("cli")
+	a.Commands = []*Command{{
+		Name: "fetch",
+		Handle: func(c *Args) int {
+			args = c
+			c.Progress().Bar("fetch", 10).Add(4)
+			c.Progress().Spinner("unpack")
+			return 0
+		},
+	}}
+	defer output.Reset()
+
+	if exitcode := a.Exec([]string{"fetch"}); exitcode != 0 {
+		t.Errorf("finished with code %d", exitcode)
+	}
+	if args.ctx != nil {
+		t.Error("Progress took over interrupts")
+	}
+	if !strings.Contains(output.String(), "fetch: 0% 0/10\nfetch: 40% 4/10\nunpack...\n") {
+		t.Errorf("unexpected output: %q", output.String())
+	}
+
+	a.Commands[0].Handle = func(c *Args) int {
+		args = c
+		c.Context()
+		c.Progress().Bar("fetch", 10).Add(10)
+		return 0
+	}
+	if exitcode := a.Exec([]string{"fetch"}); exitcode != 0 {
+		t.Errorf("finished with code %d", exitcode)
+	}
+	if args.ctx.Err() == nil {
+		t.Error("the context isn't done after the handler returned")
+	}
+}
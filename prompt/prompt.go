@@ -0,0 +1,300 @@
+// Package prompt asks users questions on a terminal: yes/no
+// confirmations, text input, passwords and choices from a list.
+//
+// Prompts fail with ErrNotTerminal when the input is not a terminal,
+// so scripts never hang waiting for an answer, and with ErrNoInput
+// when prompting is turned off, e.g. with the --no-input flag.
+package prompt
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+
+	"github.com/ccpaging/cli/internal/term"
+)
+
+var (
+	// ErrNotTerminal is returned when the input is not a terminal.
+	ErrNotTerminal = errors.New("can't prompt, the input is not a terminal")
+
+	// ErrNoInput is returned when prompting is turned off.
+	ErrNoInput = errors.New("can't prompt, input is turned off")
+)
+
+// isTerminal reports whether r is a terminal, replaced in tests.
+var isTerminal = func(r io.Reader) bool {
+	f, ok := r.(*os.File)
+	return ok && term.IsTerminal(f.Fd())
+}
+
+// Prompter asks questions on Out and reads answers from In.
+type Prompter struct {
+	In  io.Reader
+	Out io.Writer
+
+	// AssumeYes answers yes to confirmations without asking.
+	AssumeYes bool
+
+	// NoInput turns prompting off: everything but confirmations
+	// answered by AssumeYes fails with ErrNoInput.
+	NoInput bool
+
+	// IsTerminal reports whether questions can be asked on In. If
+	// nil, In must be a terminal.
+	IsTerminal func() bool
+
+	reader *bufio.Reader
+}
+
+// New returns a prompter asking on the standard streams.
+func New() *Prompter {
+	return &Prompter{In: os.Stdin, Out: os.Stdout}
+}
+
+// check reports whether questions can be asked.
+func (p *Prompter) check() error {
+	if p.NoInput {
+		return ErrNoInput
+	}
+	terminal := p.IsTerminal
+	if terminal == nil {
+		terminal = func() bool { return isTerminal(p.In) }
+	}
+	if !terminal() {
+		return ErrNotTerminal
+	}
+	if p.reader == nil {
+		p.reader = bufio.NewReader(p.In)
+	}
+	return nil
+}
+
+// readLine reads an answer, without the line break.
+func (p *Prompter) readLine() (string, error) {
+	line, err := p.reader.ReadString('\n')
+	if err == io.EOF && line != "" {
+		err = nil
+	}
+	return strings.TrimSpace(line), err
+}
+
+// errInvalid is returned by parsers of ask for answers not accepted.
+var errInvalid = errors.New("invalid answer")
+
+// ask prints the question until the answer is accepted by parse. The
+// retry text is shown before asking again, or the error of parse if
+// there's no such text.
+func (p *Prompter) ask(question, retry string, parse func(answer string) error) error {
+	for {
+		fmt.Fprint(p.Out, question)
+		answer, err := p.readLine()
+		if err != nil {
+			return err
+		}
+		if err := parse(answer); err != nil {
+			if retry != "" {
+				fmt.Fprintln(p.Out, retry)
+			} else {
+				fmt.Fprintln(p.Out, err)
+			}
+			continue
+		}
+		return nil
+	}
+}
+
+// Confirm asks a yes/no question. An empty answer means def.
+func (p *Prompter) Confirm(question string, def bool) (bool, error) {
+	if p.AssumeYes {
+		return true, nil
+	}
+	if err := p.check(); err != nil {
+		return false, err
+	}
+
+	hint := " [y/N] "
+	if def {
+		hint = " [Y/n] "
+	}
+	answer := def
+	err := p.ask(question+hint, "Please answer yes or no.", func(s string) error {
+		switch strings.ToLower(s) {
+		case "":
+		case "y", "yes":
+			answer = true
+		case "n", "no":
+			answer = false
+		default:
+			return errInvalid
+		}
+		return nil
+	})
+	return answer, err
+}
+
+// Input asks for a line of text. An empty answer means def. Validate,
+// if not nil, rejects answers by returning an error, which is shown
+// before asking again.
+func (p *Prompter) Input(question, def string, validate func(string) error) (string, error) {
+	if err := p.check(); err != nil {
+		return "", err
+	}
+
+	if def != "" {
+		question += " [" + def + "]"
+	}
+	var answer string
+	err := p.ask(question+": ", "", func(s string) error {
+		if s == "" {
+			s = def
+		}
+		if validate != nil {
+			if err := validate(s); err != nil {
+				return err
+			}
+		}
+		answer = s
+		return nil
+	})
+	return answer, err
+}
+
+// Password asks for a secret without echoing it, where the platform
+// allows that. The terminal is restored even if the process gets
+// interrupted while waiting for the answer.
+func (p *Prompter) Password(question string) (string, error) {
+	if err := p.check(); err != nil {
+		return "", err
+	}
+
+	if f, ok := p.In.(*os.File); ok {
+		if state, err := term.DisableEcho(f.Fd()); err == nil {
+			defer p.restoreOnInterrupt(f.Fd(), state)()
+		}
+	}
+
+	fmt.Fprint(p.Out, question+": ")
+	line, err := p.reader.ReadString('\n')
+	if err == io.EOF && line != "" {
+		err = nil
+	}
+	return strings.TrimRight(line, "\r\n"), err
+}
+
+// restoreOnInterrupt restores the terminal state if the process gets
+// interrupted, before exiting with the status of shells for SIGINT.
+// The function returned restores it and stops waiting.
+func (p *Prompter) restoreOnInterrupt(fd uintptr, state *term.State) (restore func()) {
+	interrupt := make(chan os.Signal, 1)
+	signal.Notify(interrupt, os.Interrupt)
+	done := make(chan struct{})
+	go func() {
+		select {
+		case <-interrupt:
+			term.Restore(fd, state)
+			fmt.Fprintln(p.Out)
+			os.Exit(130)
+		case <-done:
+		}
+	}()
+
+	return func() {
+		signal.Stop(interrupt)
+		close(done)
+		term.Restore(fd, state)
+		fmt.Fprintln(p.Out)
+	}
+}
+
+// Select asks to pick one of the options by number and returns its
+// index. An empty answer means def, unless it's negative.
+func (p *Prompter) Select(question string, options []string, def int) (int, error) {
+	if err := p.check(); err != nil {
+		return 0, err
+	}
+	if len(options) == 0 {
+		return 0, errors.New("nothing to select from")
+	}
+
+	p.list(question, options)
+	hint := "Choice: "
+	if def >= 0 && def < len(options) {
+		hint = "Choice [" + strconv.Itoa(def+1) + "]: "
+	}
+
+	choice := def
+	retry := fmt.Sprintf("Please enter a number from 1 to %d.", len(options))
+	err := p.ask(hint, retry, func(s string) error {
+		if s == "" && def >= 0 && def < len(options) {
+			return nil
+		}
+		n, err := strconv.Atoi(s)
+		if err != nil || n < 1 || n > len(options) {
+			return errInvalid
+		}
+		choice = n - 1
+		return nil
+	})
+	return choice, err
+}
+
+// MultiSelect asks to pick any of the options by numbers separated by
+// commas or spaces and returns their indexes. An empty answer means
+// defaults.
+func (p *Prompter) MultiSelect(question string, options []string, defaults []int) ([]int, error) {
+	if err := p.check(); err != nil {
+		return nil, err
+	}
+	if len(options) == 0 {
+		return nil, errors.New("nothing to select from")
+	}
+
+	p.list(question, options)
+	hint := "Choices: "
+	if len(defaults) > 0 {
+		numbers := make([]string, len(defaults))
+		for i, index := range defaults {
+			numbers[i] = strconv.Itoa(index + 1)
+		}
+		hint = "Choices [" + strings.Join(numbers, ",") + "]: "
+	}
+
+	var choices []int
+	retry := fmt.Sprintf("Please enter numbers from 1 to %d.", len(options))
+	err := p.ask(hint, retry, func(s string) error {
+		if s == "" {
+			choices = defaults
+			return nil
+		}
+
+		choices = nil
+		picked := make(map[int]bool)
+		for _, field := range strings.FieldsFunc(s, func(r rune) bool { return r == ',' || r == ' ' }) {
+			n, err := strconv.Atoi(field)
+			if err != nil || n < 1 || n > len(options) {
+				return errInvalid
+			}
+			if !picked[n-1] {
+				picked[n-1] = true
+				choices = append(choices, n-1)
+			}
+		}
+		return nil
+	})
+	return choices, err
+}
+
+// list prints the question followed by numbered options.
+func (p *Prompter) list(question string, options []string) {
+	fmt.Fprintln(p.Out, question)
+	width := len(strconv.Itoa(len(options)))
+	for i, option := range options {
+		fmt.Fprintf(p.Out, "  %*d) %s\n", width, i+1, option)
+	}
+}
@@ -0,0 +1,99 @@
+package prompt
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"testing"
+)
+
+func newPrompter(input string) (*Prompter, *strings.Builder) {
+	out := new(strings.Builder)
+	return &Prompter{In: strings.NewReader(input), Out: out}, out
+}
+
+func TestPrompter(t *testing.T) {
+	defer func(original func(io.Reader) bool) { isTerminal = original }(isTerminal)
+	isTerminal = func(io.Reader) bool { return true }
+
+	p, out := newPrompter("maybe\ny\n\n")
+	if yes, err := p.Confirm("Remove?", false); !yes || err != nil {
+		t.Errorf("confirmation is %v, %v", yes, err)
+	}
+	if yes, err := p.Confirm("Remove?", true); !yes || err != nil {
+		t.Errorf("default confirmation is %v, %v", yes, err)
+	}
+	if expected := "Remove? [y/N] Please answer yes or no.\nRemove? [y/N] Remove? [Y/n] "; out.String() != expected {
+		t.Errorf("unexpected output: %q", out.String())
+	}
+
+	p, out = newPrompter("x\n\n")
+	name, err := p.Input("Name", "gopher", func(s string) error {
+		if len(s) < 2 {
+			return errors.New("Too short.")
+		}
+		return nil
+	})
+	if name != "gopher" || err != nil {
+		t.Errorf("input is %q, %v", name, err)
+	}
+	if expected := "Name [gopher]: Too short.\nName [gopher]: "; out.String() != expected {
+		t.Errorf("unexpected output: %q", out.String())
+	}
+
+	p, _ = newPrompter("s3cr3t \n")
+	if password, err := p.Password("Password"); password != "s3cr3t " || err != nil {
+		t.Errorf("password is %q, %v", password, err)
+	}
+
+	p, out = newPrompter("4\n2\n")
+	if choice, err := p.Select("Color:", []string{"red", "green", "blue"}, 0); choice != 1 || err != nil {
+		t.Errorf("choice is %d, %v", choice, err)
+	}
+	expected := "Color:\n  1) red\n  2) green\n  3) blue\nChoice [1]: Please enter a number from 1 to 3.\nChoice [1]: "
+	if out.String() != expected {
+		t.Errorf("unexpected output: %q", out.String())
+	}
+
+	p, _ = newPrompter("3, 1 3\n\n")
+	if choices, err := p.MultiSelect("Colors:", []string{"red", "green", "blue"}, []int{1}); len(choices) != 2 || choices[0] != 2 || choices[1] != 0 || err != nil {
+		t.Errorf("choices are %v, %v", choices, err)
+	}
+	if choices, err := p.MultiSelect("Colors:", []string{"red", "green", "blue"}, []int{1}); len(choices) != 1 || choices[0] != 1 || err != nil {
+		t.Errorf("default choices are %v, %v", choices, err)
+	}
+
+	p, _ = newPrompter("")
+	if _, err := p.Input("Name", "", nil); err != io.EOF {
+		t.Errorf("unexpected error at the end of input: %v", err)
+	}
+}
+
+func TestPrompter_NotInteractive(t *testing.T) {
+	p, out := newPrompter("y\n")
+	if _, err := p.Confirm("Remove?", true); err != ErrNotTerminal {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if _, err := p.Select("Color:", []string{"red"}, 0); err != ErrNotTerminal {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	p.IsTerminal = func() bool { return true }
+	if yes, err := p.Confirm("Remove?", false); !yes || err != nil {
+		t.Errorf("confirmation through IsTerminal is %v, %v", yes, err)
+	}
+	out.Reset()
+	p.IsTerminal = nil
+
+	p.NoInput = true
+	if _, err := p.Password("Password"); err != ErrNoInput {
+		t.Errorf("unexpected error: %v", err)
+	}
+	p.AssumeYes = true
+	if yes, err := p.Confirm("Remove?", false); !yes || err != nil {
+		t.Errorf("assumed confirmation is %v, %v", yes, err)
+	}
+	if out.Len() > 0 {
+		t.Errorf("unexpected output: %q", out.String())
+	}
+}
@@ -0,0 +1,50 @@
+package cli
+
+import (
+	"os"
+
+	"github.com/ccpaging/cli/internal/term"
+	"github.com/ccpaging/cli/prompt"
+)
+
+// Standard flags Args.Prompter honors. Add them to App.Flags to let
+// users answer confirmations up front or turn prompting off:
+//
+//	$ app remove --yes
+//	$ app login --no-input
+var (
+	YesFlag = &Flag{
+		Name:  "yes",
+		Short: "y",
+		Usage: "--yes",
+		Help:  "Answer yes to all confirmations.",
+		Value: switchValue{},
+	}
+	NoInputFlag = &Flag{
+		Name:  "no-input",
+		Usage: "--no-input",
+		Help:  "Never prompt, fail instead.",
+		Value: switchValue{},
+	}
+)
+
+// stdinTerminal reports whether Stdin is a terminal, replaced in tests.
+var stdinTerminal = func() bool {
+	f, ok := Stdin.(*os.File)
+	return ok && term.IsTerminal(f.Fd())
+}
+
+// Prompter returns a prompter asking on Stdout and reading answers
+// from Stdin, honoring the --yes and --no-input flags.
+func (c *Args) Prompter() *prompt.Prompter {
+	if c.prompter == nil {
+		c.prompter = &prompt.Prompter{
+			In:         Stdin,
+			Out:        Stdout,
+			AssumeYes:  c.Bool(YesFlag.Name),
+			NoInput:    c.Bool(NoInputFlag.Name),
+			IsTerminal: stdinTerminal,
+		}
+	}
+	return c.prompter
+}
@@ -0,0 +1,45 @@
+package cli
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/ccpaging/cli/prompt"
+)
+
+func TestArgs_Prompter(t *testing.T) {
+	var confirmed bool
+	var err error
+	var files []string
+	a := NewApp("cli")
+	a.Flags = []*Flag{YesFlag, NoInputFlag}
+	a.Commands = []*Command{{
+		Name:       "remove",
+		Positional: true,
+		Handle: func(args *Args) int {
+			files = args.Arguments()
+			confirmed, err = args.Prompter().Confirm("Remove?", false)
+			return 0
+		},
+	}}
+
+	a.Exec([]string{"remove", "-y"})
+	if !confirmed || err != nil {
+		t.Errorf("--yes resulted in %v, %v", confirmed, err)
+	}
+	confirmed = false
+	if exitcode := a.Exec([]string{"remove", "--yes", "file.txt"}); exitcode != 0 || !confirmed {
+		t.Errorf("--yes followed by an argument finished with code %d, confirmed %v", exitcode, confirmed)
+	}
+	if !reflect.DeepEqual(files, []string{"file.txt"}) {
+		t.Errorf("--yes took the argument following it: %v", files)
+	}
+	a.Exec([]string{"--no-input", "remove"})
+	if err != prompt.ErrNoInput {
+		t.Errorf("--no-input resulted in %v", err)
+	}
+	a.Exec([]string{"remove"})
+	if err != prompt.ErrNotTerminal {
+		t.Errorf("prompting without a terminal resulted in %v", err)
+	}
+}
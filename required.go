@@ -0,0 +1,66 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/ccpaging/cli/prompt"
+)
+
+// askRequired asks for the values of the required flags missing,
+// unless bound fields get them from the environment. If users can't
+// be asked, the first flag missing is a usage error.
+func askRequired(ctx *Args, flags []*Flag, bound []binding) error {
+	for _, flag := range flags {
+		if !flag.Required || ctx.Has(flag.Name) || fromEnv(bound, flag) {
+			continue
+		}
+
+		value, err := askFlag(ctx.Prompter(), flag)
+		if errors.Is(err, prompt.ErrNotTerminal) || errors.Is(err, prompt.ErrNoInput) {
+			return fmt.Errorf(`option --%s is required`, flag.Name)
+		}
+		if err != nil {
+			return err
+		}
+		ctx.vars[flag.Name] = value
+	}
+	return nil
+}
+
+// askFlag asks for the value of the flag, offering its choices, if it
+// has any. Values are checked by the flag's Value.
+func askFlag(p *prompt.Prompter, flag *Flag) (string, error) {
+	question := strings.TrimRight(flag.Help, ".")
+	if question == "" {
+		question = "Value of --" + flag.Name
+	}
+
+	if len(flag.Choices) > 0 {
+		for {
+			i, err := p.Select(question+":", flag.Choices, -1)
+			if err != nil {
+				return "", err
+			}
+			if flag.Value == nil {
+				return flag.Choices[i], nil
+			}
+			if err := setValue(flag, flag.Choices[i], true); err != nil {
+				fmt.Fprintln(p.Out, err)
+				continue
+			}
+			return flag.Choices[i], nil
+		}
+	}
+
+	return p.Input(question, flag.DefValue, func(value string) error {
+		if value == "" {
+			return fmt.Errorf(`option --%s is required`, flag.Name)
+		}
+		if flag.Value != nil {
+			return setValue(flag, value, true)
+		}
+		return nil
+	})
+}
@@ -0,0 +1,98 @@
+package cli
+
+import (
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestRequired(t *testing.T) {
+	var format string
+	a := NewApp("cli")
+	a.Color = ColorNever
+	a.Commands = []*Command{{
+		Name: "export",
+		Flags: []*Flag{
+			{Name: "format", Short: "f", Required: true, Choices: []string{"json", "yaml", "toml"}},
+		},
+		Handle: func(args *Args) int {
+			format = args.String("format")
+			return 0
+		},
+	}}
+	defer output.Reset()
+
+	if exitcode := a.Exec([]string{"export", "-f", "yaml"}); exitcode != 0 || format != "yaml" {
+		t.Errorf("finished with code %d and format %q", exitcode, format)
+	}
+
+	check := func(arguments []string, expected string) {
+		output.Reset()
+		if exitcode := a.Exec(arguments); exitcode != 1 {
+			t.Errorf("%v finished with code %d, expected 1", arguments, exitcode)
+		}
+		if !strings.Contains(output.String(), expected) {
+			t.Errorf("unexpected output of %v: %q", arguments, output.String())
+		}
+	}
+
+	check([]string{"export"}, "cli: error: option --format is required\n")
+	check([]string{"export", "--format=xml"}, `cli: error: invalid value "xml" for option --format, expected json, yaml or toml`)
+}
+
+func TestRequired_Ask(t *testing.T) {
+	defer func(saved func() bool) { stdinTerminal = saved }(stdinTerminal)
+	stdinTerminal = func() bool { return true }
+	defer func() { Stdin = os.Stdin }()
+	defer output.Reset()
+
+	var format string
+	var limit byteSize
+	var options struct {
+		Token string `cli:"token,env=CLI_TEST_TOKEN,required" help:"API token."`
+	}
+	a := NewApp("cli")
+	a.Color = ColorNever
+	a.Commands = []*Command{{
+		Name: "export",
+		Flags: []*Flag{
+			{Name: "format", Required: true, Help: "Output format.", Choices: []string{"json", "yaml"}},
+			{Name: "limit", Required: true, Help: "Size limit.", Value: &limit},
+		},
+		Bind: &options,
+		Handle: func(args *Args) int {
+			format = args.String("format")
+			return 0
+		},
+	}}
+
+	check := func(input, expected string, arguments ...string) {
+		t.Helper()
+		output.Reset()
+		Stdin = strings.NewReader(input)
+		if exitcode := a.Exec(append([]string{"export"}, arguments...)); exitcode != 0 {
+			t.Errorf("%v finished with code %d", arguments, exitcode)
+		}
+		if output.String() != expected {
+			t.Errorf("unexpected prompts of %v: %q", arguments, output.String())
+			t.Logf("- expected: %q", expected)
+		}
+	}
+
+	check("3\n2\nlots\n\n2k\nsecret\n",
+		"Output format:\n  1) json\n  2) yaml\n"+
+			"Choice: Please enter a number from 1 to 2.\nChoice: "+
+			"Size limit: invalid value \"lots\" for option --limit: expected a number of bytes, e.g. 10k\n"+
+			"Size limit: option --limit is required\n"+
+			"Size limit: "+
+			"API token: ")
+	if format != "yaml" || limit != 2<<10 || options.Token != "secret" {
+		t.Errorf("answers resulted in %q, %d and %q", format, limit, options.Token)
+	}
+
+	t.Setenv("CLI_TEST_TOKEN", "from env")
+	check("", "", "--format=json", "--limit=1k")
+	if options.Token != "from env" {
+		t.Errorf("required field isn't taken from the environment: %q", options.Token)
+	}
+}
@@ -0,0 +1,145 @@
+package cli
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+)
+
+const scriptUsage = "run-script [-k|--keep-going] [-v|--verbose] FILE"
+
+// runScript executes the `run-script` command: every line of the file
+// (or Stdin, if the file is `-`) is executed as if it was given to Run.
+//
+// Execution stops at the first command failing, unless --keep-going
+// is given. Failures are reported with the line number and the exit
+// status, --verbose reports every line. Global flags are accepted
+// after it, like after any other command.
+func (a *App) runScript(arguments []string) (exitCode int, fatal bool) {
+	own := []*Flag{
+		{Name: "keep-going", Short: "k", Value: switchValue{}},
+		{Name: "verbose", Short: "v", Value: switchValue{}},
+	}
+	inherited := a.inheritedFlags(own)
+	parsed, err := parseArguments(true, true, append(own, inherited...), arguments)
+	if err != nil || len(parsed.arguments) != 1 {
+		a.printerr("usage: " + a.Name + " " + scriptUsage)
+		return 1, true
+	}
+	if err := a.applyGlobals(parsed.vars, inherited); err != nil {
+		a.printerr(err)
+		return 1, true
+	}
+
+	args := &Args{app: a, vars: parsed.vars}
+	file, keepGoing, verbose := parsed.arguments[0], args.Bool("keep-going"), args.Bool("verbose")
+
+	var r io.Reader = Stdin
+	if file != "-" {
+		f, err := os.Open(file)
+		if err != nil {
+			a.printerr(err)
+			return 1, true
+		}
+		defer f.Close()
+		r = f
+	} else {
+		file = "<stdin>"
+	}
+
+	return a.execScript(r, file, keepGoing, verbose), false
+}
+
+// execScript executes the script line by line and returns the exit
+// code of the last command failing, if any.
+//
+// Lines ending with a backslash continue on the next line. Empty lines
+// and comments are skipped. `exit [code]` stops the script.
+func (a *App) execScript(r io.Reader, name string, keepGoing, verbose bool) (exitCode int) {
+	scanner := bufio.NewScanner(r)
+	var (
+		number  int
+		start   int
+		logical strings.Builder
+	)
+
+	for scanner.Scan() {
+		number++
+		line := scanner.Text()
+		if logical.Len() == 0 {
+			start = number
+		}
+		logical.WriteString(line)
+		if continued(line) {
+			logical.WriteString("\n")
+			continue
+		}
+
+		code, stop := a.execScriptLine(logical.String(), fmt.Sprintf("%s:%d", name, start), verbose)
+		logical.Reset()
+		if code != 0 {
+			exitCode = code
+		}
+		if stop || (code != 0 && !keepGoing) {
+			return exitCode
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		a.printerr(err)
+		return 1
+	}
+	if logical.Len() > 0 {
+		code, _ := a.execScriptLine(logical.String(), fmt.Sprintf("%s:%d", name, start), verbose)
+		if code != 0 {
+			exitCode = code
+		}
+	}
+	return exitCode
+}
+
+// continued reports whether line ends with an unescaped backslash.
+func continued(line string) bool {
+	n := len(line) - len(strings.TrimRight(line, `\`))
+	return n%2 == 1
+}
+
+// execScriptLine executes a single logical line of a script. Stop is
+// true if the script should stop right away.
+func (a *App) execScriptLine(line, position string, verbose bool) (exitCode int, stop bool) {
+	arguments, err := SplitExpand(line, os.Getenv)
+	if err != nil {
+		a.printerr(position + ": " + err.Error())
+		return 1, false
+	}
+	if len(arguments) == 0 {
+		return 0, false
+	}
+
+	switch arguments[0] {
+	case "exit":
+		if len(arguments) > 1 {
+			exitCode, err = strconv.Atoi(arguments[1])
+			if err != nil {
+				a.printerr(position + ": invalid exit code " + Join(arguments[1:2]))
+				return 1, true
+			}
+		}
+		return exitCode, true
+	case "shell", "run-script":
+		a.printerr(position + ": " + arguments[0] + " can't be used in scripts")
+		return 1, false
+	}
+
+	// global flags of a line only apply to that line
+	pager, color := a.Pager, a.Color
+	exitCode = a.Exec(arguments)
+	a.Pager, a.Color = pager, color
+	if exitCode != 0 || verbose {
+		fmt.Fprintf(Stderr, "%s: %s: exit status %d\n", a.Name, position, exitCode)
+	}
+	return exitCode, false
+}
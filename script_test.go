@@ -0,0 +1,93 @@
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestRunScript(t *testing.T) {
+	var opened []string
+	a := NewApp("cli")
+	a.Scripts = true
+	a.AddCommand(&Command{
+		Name:  "open",
+		Flags: []*Flag{{Name: "all", Short: "a"}},
+		Handle: func(args *Args) int {
+			opened = append(opened, args.String("all"))
+			return len(opened) - 1
+		},
+	})
+
+	script := "# opens some things\n" +
+		"open --all first\n" +
+		"\n" +
+		"open \\\n" +
+		"  -a 'second one'\n" +
+		"open -a third\n"
+
+	run := func(arguments ...string) int {
+		opened = nil
+		output.Reset()
+		Stdin = strings.NewReader(script)
+		defer func() { Stdin = os.Stdin }()
+		setArguments(arguments...)
+		defer setArguments()
+		return a.Run()
+	}
+	defer output.Reset()
+
+	if exitcode := run("run-script", "-"); exitcode != 1 {
+		t.Errorf("finished with code %d, expected 1", exitcode)
+	}
+	if !reflect.DeepEqual(opened, []string{"first", "second one"}) {
+		t.Errorf("unexpected commands executed: %v", opened)
+	}
+	if output.String() != "cli: <stdin>:4: exit status 1\n" {
+		t.Errorf("unexpected output: %q", output.String())
+	}
+
+	if exitcode := run("run-script", "--keep-going", "-"); exitcode != 2 {
+		t.Errorf("finished with code %d, expected 2", exitcode)
+	}
+	if !reflect.DeepEqual(opened, []string{"first", "second one", "third"}) {
+		t.Errorf("unexpected commands executed: %v", opened)
+	}
+
+	file := filepath.Join(t.TempDir(), "script")
+	if err := os.WriteFile(file, []byte("open\nexit 7\nopen\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if exitcode := run("run-script", "-v", file); exitcode != 7 {
+		t.Errorf("finished with code %d, expected 7", exitcode)
+	}
+	if output.String() != "cli: "+file+":1: exit status 0\n" {
+		t.Errorf("unexpected output: %q", output.String())
+	}
+
+	script = "--no-pager --color=always open\nopen\n"
+	if exitcode := run("run-script", "-", "--color=never"); exitcode != 1 {
+		t.Errorf("finished with code %d, expected 1", exitcode)
+	}
+	if !reflect.DeepEqual(opened, []string{"", ""}) {
+		t.Errorf("unexpected commands executed: %v", opened)
+	}
+	if !a.Pager || a.Color != ColorNever {
+		t.Errorf("global flags of a line outlive it: pager %v, color %q", a.Pager, a.Color)
+	}
+}
+
+func TestContinued(t *testing.T) {
+	for line, expected := range map[string]bool{
+		`open`:     false,
+		`open \`:   true,
+		`open \\`:  false,
+		`open \\\`: true,
+	} {
+		if continued(line) != expected {
+			t.Errorf("continued(%q) != %v", line, expected)
+		}
+	}
+}
@@ -0,0 +1,183 @@
+package cli
+
+import (
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// Search weights of the places a keyword can be found in.
+const (
+	weightName    = 50
+	weightBrief   = 20
+	weightHelp    = 10
+	weightFlag    = 10
+	weightExample = 5
+	weightText    = 10
+)
+
+// snippetWidth is the maximal length of a search result snippet.
+const snippetWidth = 60
+
+// maxWordCut is the most a snippet gets shortened by to keep words
+// at its edges whole.
+const maxWordCut = snippetWidth / 4
+
+// searchResult is a command or a topic matching the keyword.
+type searchResult struct {
+	name    string
+	brief   string
+	snippet string
+	score   int
+}
+
+// matcher accumulates the score of a single command or topic.
+type matcher struct {
+	keyword string
+	result  searchResult
+}
+
+// match scores text and remembers the first snippet found.
+func (m *matcher) match(text string, weight int, snippet bool) {
+	n := strings.Count(strings.ToLower(text), m.keyword)
+	if n == 0 {
+		return
+	}
+
+	m.result.score += n * weight
+	if snippet && m.result.snippet == "" {
+		m.result.snippet = snippetOf(text, m.keyword)
+	}
+}
+
+// search looks for the keyword in commands and topics. Results are
+// ordered by their relevance.
+func (a *App) search(keyword string) []searchResult {
+	keyword = strings.ToLower(strings.TrimSpace(keyword))
+	if keyword == "" {
+		return nil
+	}
+
+	var results []searchResult
+	for _, command := range a.Commands {
+		m := matcher{keyword: keyword, result: searchResult{name: command.Name, brief: command.Brief}}
+		m.match(command.Name, weightName, false)
+		m.match(command.Brief, weightBrief, false)
+		m.match(command.Help, weightHelp, true)
+		for _, flag := range command.allFlags() {
+			m.match(flag.Name, weightFlag, true)
+			m.match(flag.Help, weightFlag, true)
+		}
+		for _, example := range command.Examples {
+			m.match(example.Usecase, weightExample, true)
+			m.match(example.Description, weightExample, true)
+		}
+		if m.result.score > 0 {
+			results = append(results, m.result)
+		}
+	}
+
+	walkTopics("", a.Topics, func(name string, topic *Topic) {
+		m := matcher{keyword: keyword, result: searchResult{name: name, brief: topic.Brief}}
+		m.match(topic.Name, weightName, false)
+		m.match(topic.Brief, weightBrief, false)
+		m.match(topic.Text, weightText, true)
+		if m.result.score > 0 {
+			results = append(results, m.result)
+		}
+	})
+
+	sort.SliceStable(results, func(i, j int) bool {
+		return results[i].score > results[j].score
+	})
+	return results
+}
+
+// snippetOf returns the line of text containing the keyword, cut down
+// to the snippet width around the first occurrence of the keyword.
+// Cuts are made at word boundaries.
+func snippetOf(text, keyword string) string {
+	keyword = strings.Join(strings.Fields(keyword), " ")
+	var line string
+	for _, each := range strings.Split(text, "\n") {
+		each = strings.Join(strings.Fields(each), " ")
+		if strings.Contains(strings.ToLower(each), keyword) {
+			line = each
+			break
+		}
+	}
+
+	runes := []rune(line)
+	if len(runes) <= snippetWidth {
+		return line
+	}
+
+	lower := strings.ToLower(line)
+	index := strings.Index(lower, keyword)
+	if index < 0 {
+		return strings.TrimSpace(string(runes[:snippetWidth])) + "..."
+	}
+	at := utf8.RuneCountInString(lower[:index])
+	start := at - (snippetWidth-utf8.RuneCountInString(keyword))/2
+	if start < 0 {
+		start = 0
+	}
+	end := start + snippetWidth
+	if end > len(runes) {
+		end = len(runes)
+		start = end - snippetWidth
+	}
+
+	// Don't cut words in half, unless they're too long to drop.
+	if i := wordStart(runes, start, at); i-start <= maxWordCut {
+		start = i
+	}
+	if i := wordEnd(runes, end, at+utf8.RuneCountInString(keyword)); end-i <= maxWordCut {
+		end = i
+	}
+
+	snippet := strings.TrimSpace(string(runes[start:end]))
+	if start > 0 {
+		snippet = "..." + snippet
+	}
+	if end < len(runes) {
+		snippet += "..."
+	}
+	return snippet
+}
+
+// wordStart returns the start of the first word beginning at or after
+// start, but not after limit.
+func wordStart(runes []rune, start, limit int) int {
+	for start > 0 && start < limit && runes[start-1] != ' ' {
+		start++
+	}
+	return start
+}
+
+// wordEnd returns the end of the last word ending at or before end,
+// but not before limit.
+func wordEnd(runes []rune, end, limit int) int {
+	for end < len(runes) && end > limit && runes[end] != ' ' {
+		end--
+	}
+	return end
+}
+
+func (a *App) searchHelp(keyword string, results []searchResult) string {
+	theme := a.theme(Stdout)
+	page := newLayout(theme)
+	page.heading(`Commands and topics matching "` + keyword + `":`)
+
+	list := definitionList{termStyle: theme.Name}
+	for _, result := range results {
+		term := result.name
+		if result.brief != "" {
+			term += " - " + result.brief
+		}
+		list.items = append(list.items, definition{term, result.snippet})
+	}
+	page.add(list)
+
+	return page.String()
+}
@@ -0,0 +1,86 @@
+package cli
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSearch(t *testing.T) {
+	a := NewApp("cli")
+	a.AddCommand(&Command{
+		Name:  "join",
+		Brief: "merges the strings given",
+		Flags: []*Flag{
+			{Name: "separator", Help: "Put some separating string between all the strings given."},
+		},
+	})
+	a.AddCommand(&Command{Name: "split", Brief: "splits a string by separator"})
+	a.AddCommand(&Command{Name: "open", Brief: "opens smth"})
+	a.AddTopic(&Topic{
+		Name:  "strings",
+		Brief: "how strings work",
+		Text:  "Strings are sequences of bytes.\n\nA very, very long line mentioning the separating character somewhere in its middle, which is long.",
+	})
+
+	var names []string
+	for _, result := range a.search("SEPARAT") {
+		names = append(names, result.name)
+	}
+	if strings.Join(names, " ") != "join split strings" {
+		t.Errorf("unexpected search results: %v", names)
+	}
+
+	results := a.search("separating")
+	if results[0].snippet != "Put some separating string between all the strings given." {
+		t.Errorf("unexpected snippet: %q", results[0].snippet)
+	}
+	if results[1].snippet != "...long line mentioning the separating character somewhere in..." {
+		t.Errorf("unexpected snippet: %q", results[1].snippet)
+	}
+
+	if results := a.search("nothing like that"); len(results) != 0 {
+		t.Errorf("unexpected search results: %v", results)
+	}
+}
+
+func TestSearch_SpacedKeyword(t *testing.T) {
+	a := NewApp("cli")
+	a.AddTopic(&Topic{
+		Name: "strings",
+		Text: "A very, very long line mentioning the separating  character somewhere\tin its middle, which is long.\n" +
+			strings.Repeat("a", 50) + " needle " + strings.Repeat("b", 50),
+	})
+
+	check := func(keyword, expected string) {
+		t.Helper()
+		results := a.search(keyword)
+		if len(results) != 1 {
+			t.Fatalf("%q: unexpected search results: %v", keyword, results)
+		}
+		if results[0].snippet != expected {
+			t.Errorf("%q: unexpected snippet: %q", keyword, results[0].snippet)
+		}
+	}
+	check("separating  character", "...line mentioning the separating character somewhere in its...")
+	check("needle", "..."+strings.Repeat("a", 26)+" needle "+strings.Repeat("b", 26)+"...")
+	check("somewhere\tin", "...separating character somewhere in its middle, which is...")
+}
+
+func TestRun_HelpKeyword(t *testing.T) {
+	a := NewApp("cli")
+	a.AddCommand(&Command{Name: "open", Brief: "opens smth", Help: "Opens smth\nby its name."})
+	setArguments("help", "-k", "name")
+	defer setArguments()
+	defer output.Reset()
+
+	if exitcode := a.Run(); exitcode != 0 {
+		t.Errorf("finished with code %d, expected 0", exitcode)
+	}
+
+	expected := "Commands and topics matching \"name\":\n\n\topen - opens smth\n\t\tby its name.\n\n"
+	if output.String() != expected {
+		t.Errorf("unexpected search output:")
+		t.Logf("- expected: %q", expected)
+		t.Logf("- recieved: %q", output.String())
+	}
+}
@@ -0,0 +1,157 @@
+package cli
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/ccpaging/cli/internal/readline"
+)
+
+// maxHistory is the number of lines kept in the shell history file.
+const maxHistory = 1000
+
+// historyFile returns the path to the shell history file.
+func (a *App) historyFile() string {
+	if a.HistoryFile != "" {
+		return a.HistoryFile
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, "."+a.Name+"_history")
+}
+
+func loadHistory(path string) []string {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil
+	}
+	defer f.Close()
+
+	var history []string
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		if line := scanner.Text(); line != "" {
+			history = append(history, line)
+		}
+	}
+	return history
+}
+
+func saveHistory(path string, history []string) error {
+	if len(history) > maxHistory {
+		history = history[len(history)-maxHistory:]
+	}
+	return os.WriteFile(path, []byte(strings.Join(history, "\n")+"\n"), 0600)
+}
+
+// shell runs the interactive shell: every line typed is executed
+// as if it was given to Run, until the input is over or the user
+// types `exit`.
+func (a *App) shell() int {
+	history := a.historyFile()
+	editor := &readline.Editor{
+		In:       Stdin,
+		Out:      Stdout,
+		Prompt:   a.Name + "> ",
+		History:  loadHistory(history),
+		Complete: a.complete,
+	}
+
+	for {
+		line, err := editor.ReadLine()
+		if errors.Is(err, readline.ErrInterrupt) {
+			continue
+		}
+		if err == io.EOF {
+			return 0
+		}
+		if err != nil {
+			a.printerr(err)
+			return 1
+		}
+
+		arguments, err := SplitExpand(line, os.Getenv)
+		if err != nil {
+			a.printerr(err)
+			continue
+		}
+		if len(arguments) == 0 {
+			continue
+		}
+
+		editor.History = append(editor.History, strings.TrimSpace(line))
+		if history != "" {
+			saveHistory(history, editor.History)
+		}
+
+		switch arguments[0] {
+		case "exit", "quit":
+			return 0
+		case "shell":
+			a.printerr("already in the shell")
+			continue
+		}
+
+		// global flags of a line only apply to that line
+		pager, color := a.Pager, a.Color
+		a.Exec(arguments)
+		a.Pager, a.Color = pager, color
+	}
+}
+
+// complete returns candidates for the last word of the line: command
+// and topic names for the first word or the one after `help`, flags
+// of the command otherwise.
+func (a *App) complete(line string) []string {
+	words := strings.Fields(line)
+	if len(words) == 0 || strings.HasSuffix(line, " ") {
+		words = append(words, "")
+	}
+	word := words[len(words)-1]
+
+	var candidates []string
+	switch {
+	case len(words) == 1:
+		candidates = append(candidates, "help", "version", "exit")
+		if a.Shell {
+			candidates = append(candidates, "shell")
+		}
+		if a.Scripts {
+			candidates = append(candidates, "run-script")
+		}
+		for _, command := range a.Commands {
+			candidates = append(candidates, command.Name)
+		}
+	case len(words) == 2 && words[0] == "help":
+		for _, command := range a.Commands {
+			candidates = append(candidates, command.Name)
+		}
+		walkTopics("", a.Topics, func(path string, topic *Topic) {
+			candidates = append(candidates, path)
+		})
+	case strings.HasPrefix(word, "-"):
+		if command := a.commandByName(words[0]); command != nil {
+			own := command.allFlags()
+			for _, flag := range append(own, a.inheritedFlags(own)...) {
+				candidates = append(candidates, "--"+flag.Name)
+				if flag.Short != "" {
+					candidates = append(candidates, "-"+flag.Short)
+				}
+			}
+		}
+	}
+
+	var matches []string
+	for _, candidate := range candidates {
+		if strings.HasPrefix(candidate, word) {
+			matches = append(matches, candidate)
+		}
+	}
+	return matches
+}
@@ -0,0 +1,89 @@
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestShell(t *testing.T) {
+	var opened []string
+	a := NewApp("cli")
+	a.Shell = true
+	a.HistoryFile = filepath.Join(t.TempDir(), "history")
+	a.AddCommand(&Command{
+		Name:  "open",
+		Flags: []*Flag{{Name: "all", Short: "a"}},
+		Handle: func(args *Args) int {
+			opened = append(opened, args.String("all"))
+			return 0
+		},
+	})
+
+	Stdin = strings.NewReader("open --all first\n\n# comment\nopne\nopen --nothing\n--no-pager --color=always open -a second\nexit\nopen --all never\n")
+	defer func() { Stdin = os.Stdin }()
+	setArguments("shell")
+	defer setArguments()
+	defer output.Reset()
+
+	if exitcode := a.Run(); exitcode != 0 {
+		t.Errorf("finished with code %d, expected 0", exitcode)
+	}
+	if !reflect.DeepEqual(opened, []string{"first", "second"}) {
+		t.Errorf("unexpected commands executed: %v", opened)
+	}
+	if !strings.Contains(output.String(), "unknown subcommand \"opne\"") {
+		t.Errorf("unknown subcommand isn't reported: %q", output.String())
+	}
+	if !a.Pager || a.Color != "" {
+		t.Errorf("global flags of a line outlive it: pager %v, color %q", a.Pager, a.Color)
+	}
+
+	history, err := os.ReadFile(a.HistoryFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := "open --all first\nopne\nopen --nothing\n--no-pager --color=always open -a second\nexit\n"
+	if string(history) != expected {
+		t.Errorf("unexpected history: %q", history)
+	}
+}
+
+func TestShell_Complete(t *testing.T) {
+	a := NewApp("cli")
+	a.AddCommand(&Command{Name: "open", Flags: []*Flag{{Name: "all", Short: "a"}}})
+	a.AddCommand(&Command{Name: "close"})
+	a.AddTopic(&Topic{Name: "opening", Topics: []*Topic{{Name: "doors"}}})
+
+	check := func(line string, expected ...string) {
+		if candidates := a.complete(line); !reflect.DeepEqual(candidates, expected) {
+			t.Errorf("completions of %q are %v, expected %v", line, candidates, expected)
+		}
+	}
+
+	check("o", "open")
+	check("", "help", "version", "exit", "open", "close")
+	check("help op", "open", "opening", "opening/doors")
+	check("open -", "--all", "-a", "--color", "--no-pager")
+	check("open --a", "--all")
+	check("open x")
+
+	a.Shell, a.Scripts = true, true
+	check("", "help", "version", "exit", "shell", "run-script", "open", "close")
+}
+
+func TestShell_Help(t *testing.T) {
+	a := NewApp("cli")
+	a.Shell = true
+	a.AddCommand(&Command{Name: "open", Brief: "opens smth"})
+	setArguments("help")
+	defer setArguments()
+	defer output.Reset()
+
+	a.Run()
+	if !strings.Contains(output.String(), "\topen        opens smth\n\tshell       runs commands typed interactively\n") {
+		t.Errorf("shell isn't listed in help: %q", output.String())
+	}
+}
@@ -0,0 +1,163 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// Split splits a command line into arguments, following the quoting
+// rules of a POSIX shell:
+//
+//	open 'a b' "c d" e\ f   →   [open] [a b] [c d] [e f]
+//
+// Single quotes keep everything as is, double quotes and backslashes
+// escape the next character, and a backslash before a line break is
+// a line continuation. A word starting with # begins a comment up to
+// the end of the line. Variables are not expanded, see SplitExpand.
+func Split(s string) ([]string, error) {
+	return SplitExpand(s, nil)
+}
+
+// SplitExpand is Split expanding $VAR and ${VAR} outside of single
+// quotes with mapping, e.g. os.Getenv. Expanded values are never
+// split into several arguments. Unquoted empty values make no argument,
+// unlike quoted ones: $EMPTY is dropped, "$EMPTY" is kept as "". If
+// mapping is nil, variables are not expanded.
+func SplitExpand(s string, mapping func(string) string) ([]string, error) {
+	var (
+		args   []string
+		word   strings.Builder
+		inWord bool
+	)
+	runes := []rune(s)
+
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+		switch {
+		case r == '\\':
+			i++
+			if i == len(runes) {
+				return nil, fmt.Errorf(`unfinished escape at the end of %q`, s)
+			}
+			if runes[i] != '\n' {
+				word.WriteRune(runes[i])
+				inWord = true
+			}
+
+		case r == '\'':
+			end := indexRune(runes, i+1, '\'')
+			if end < 0 {
+				return nil, fmt.Errorf(`unterminated single quote in %q`, s)
+			}
+			word.WriteString(string(runes[i+1 : end]))
+			inWord = true
+			i = end
+
+		case r == '"':
+			i++
+			for ; i < len(runes) && runes[i] != '"'; i++ {
+				switch {
+				case runes[i] == '\\' && i+1 < len(runes) && strings.ContainsRune("$`\"\\\n", runes[i+1]):
+					i++
+					if runes[i] != '\n' {
+						word.WriteRune(runes[i])
+					}
+				case runes[i] == '$' && mapping != nil:
+					i = expandVariable(runes, i, mapping, &word)
+				default:
+					word.WriteRune(runes[i])
+				}
+			}
+			if i == len(runes) {
+				return nil, fmt.Errorf(`unterminated double quote in %q`, s)
+			}
+			inWord = true
+
+		case r == '$' && mapping != nil:
+			n := word.Len()
+			i = expandVariable(runes, i, mapping, &word)
+			inWord = inWord || word.Len() > n
+
+		case r == '#' && !inWord:
+			for i < len(runes) && runes[i] != '\n' {
+				i++
+			}
+
+		case unicode.IsSpace(r):
+			if inWord {
+				args = append(args, word.String())
+				word.Reset()
+				inWord = false
+			}
+
+		default:
+			word.WriteRune(r)
+			inWord = true
+		}
+	}
+
+	if inWord {
+		args = append(args, word.String())
+	}
+	return args, nil
+}
+
+// expandVariable writes the value of the variable starting at the
+// dollar sign at i and returns the position of its last rune. A lone
+// dollar sign is kept as is.
+func expandVariable(runes []rune, i int, mapping func(string) string, word *strings.Builder) int {
+	if i+1 < len(runes) && runes[i+1] == '{' {
+		if end := indexRune(runes, i+2, '}'); end > 0 {
+			word.WriteString(mapping(string(runes[i+2 : end])))
+			return end
+		}
+	}
+
+	end := i + 1
+	for end < len(runes) && (runes[end] == '_' || unicode.IsLetter(runes[end]) || unicode.IsDigit(runes[end])) {
+		end++
+	}
+	if end == i+1 {
+		word.WriteRune('$')
+		return i
+	}
+
+	word.WriteString(mapping(string(runes[i+1 : end])))
+	return end - 1
+}
+
+func indexRune(runes []rune, from int, r rune) int {
+	for i := from; i < len(runes); i++ {
+		if runes[i] == r {
+			return i
+		}
+	}
+	return -1
+}
+
+// Join joins arguments into a command line Split turns back into the
+// same arguments. Arguments with anything but letters, digits and
+// a few safe punctuation marks are single-quoted.
+func Join(args []string) string {
+	quoted := make([]string, len(args))
+	for i, arg := range args {
+		quoted[i] = quote(arg)
+	}
+	return strings.Join(quoted, " ")
+}
+
+func quote(arg string) string {
+	if arg == "" {
+		return "''"
+	}
+
+	safe := strings.IndexFunc(arg, func(r rune) bool {
+		return !(r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r)) || strings.ContainsRune("_-+=@%:,./", r))
+	}) < 0
+	if safe {
+		return arg
+	}
+
+	return "'" + strings.ReplaceAll(arg, "'", `'\''`) + "'"
+}
@@ -0,0 +1,66 @@
+package cli
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSplit(t *testing.T) {
+	env := map[string]string{"HOME": "/home/me", "EMPTY": "", "SPACED": "a b"}
+	mapping := func(name string) string { return env[name] }
+
+	check := func(s string, expand bool, expected ...string) {
+		var m func(string) string
+		if expand {
+			m = mapping
+		}
+		args, err := SplitExpand(s, m)
+		if err != nil {
+			t.Errorf("%q failed to split: %s", s, err)
+			return
+		}
+		if !reflect.DeepEqual(args, expected) {
+			t.Errorf("%q split into %q, expected %q", s, args, expected)
+		}
+	}
+
+	check("", false)
+	check("   \t\n ", false)
+	check("open  the\tdoor", false, "open", "the", "door")
+	check(`'a b' "c d" e\ f`, false, "a b", "c d", "e f")
+	check(`'it''s' "say \"hi\"" 'back\slash'`, false, "its", `say "hi"`, `back\slash`)
+	check(`"" ''`, false, "", "")
+	check("open \\\n  door", false, "open", "door")
+	check("op\\\nen", false, "open")
+	check("open # the door", false, "open")
+	check("open a#b", false, "open", "a#b")
+	check(`$HOME '$HOME' "$HOME/x" ${HOME}y`, false, "$HOME", "$HOME", "$HOME/x", "${HOME}y")
+	check(`$HOME '$HOME' "$HOME/x" ${HOME}y`, true, "/home/me", "$HOME", "/home/me/x", "/home/mey")
+	check(`$SPACED "$EMPTY" $EMPTY x$ \$HOME`, true, "a b", "", "x$", "$HOME")
+	check(`$EMPTY`, true)
+	check(`"$EMPTY"`, true, "")
+	check(`a$EMPTY ${EMPTY}b`, true, "a", "b")
+	check("größe 'ça va'", false, "größe", "ça va")
+
+	for _, invalid := range []string{`'open`, `"open`, `open\`, `"open\"`} {
+		if _, err := Split(invalid); err == nil {
+			t.Errorf("%q split without an error", invalid)
+		}
+	}
+}
+
+func TestJoin(t *testing.T) {
+	args := []string{"open", "", "a b", "it's", `"quoted"`, "$HOME", "--name=x/y.z", "größe"}
+	line := Join(args)
+
+	expected := `open '' 'a b' 'it'\''s' '"quoted"' '$HOME' --name=x/y.z 'größe'`
+	if line != expected {
+		t.Errorf("unexpected line: %s", line)
+		t.Logf("- expected: %s", expected)
+	}
+
+	split, err := Split(line)
+	if err != nil || !reflect.DeepEqual(split, args) {
+		t.Errorf("joined line doesn't split back: %q, %v", split, err)
+	}
+}
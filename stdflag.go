@@ -0,0 +1,92 @@
+package cli
+
+import (
+	"flag"
+	"io"
+)
+
+// stdValue adapts flag.Value of the standard library to Value.
+type stdValue struct {
+	flag.Value
+	typ string
+}
+
+func (v stdValue) Type() string { return v.typ }
+
+// boolValue is a boolean Value exported to the standard library,
+// so the flag package doesn't require a value for it.
+type boolValue struct {
+	Value
+}
+
+func (boolValue) IsBoolFlag() bool { return true }
+
+// AddFlagSet adds flags of a standard library flag set, keeping their
+// defaults, help and values: the variables the flag set was built with
+// get set when the command runs.
+//
+// Imported flags may have names longer than 11 characters and dots,
+// e.g. memprofilerate or test.v. It refuses the whole set if any of
+// the flags violates the rest of the naming rules documented on Flag.
+func (cmd *Command) AddFlagSet(fs *flag.FlagSet) error {
+	var flags []*Flag
+	fs.VisitAll(func(f *flag.Flag) {
+		typ, help := flag.UnquoteUsage(f)
+		if boolean, ok := f.Value.(interface{ IsBoolFlag() bool }); ok && boolean.IsBoolFlag() {
+			typ = "bool"
+		}
+		flags = append(flags, &Flag{
+			Name:     f.Name,
+			DefValue: f.DefValue,
+			Help:     help,
+			Value:    stdValue{Value: f.Value, typ: typ},
+		})
+	})
+
+	for _, newFlag := range flags {
+		if err := validateFlag(newFlag); err != nil {
+			return err
+		}
+	}
+	cmd.Flags = append(cmd.Flags, flags...)
+	return nil
+}
+
+// FlagSet returns the flags of the command as a standard library flag
+// set, with short names defined as separate flags. Flags without
+// a Value are defined as strings.
+//
+// The flag set is meant for code still using the flag package: it
+// neither exits nor prints anything on errors.
+func (cmd *Command) FlagSet() *flag.FlagSet {
+	fs := flag.NewFlagSet(cmd.Name, flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+
+	for _, f := range cmd.allFlags() {
+		var value flag.Value
+		switch {
+		case isBoolValue(f):
+			value = boolValue{f.Value}
+		case f.Value != nil:
+			value = f.Value
+		default:
+			value = new(stringValue)
+			value.Set(f.DefValue)
+		}
+
+		fs.Var(value, f.Name, f.Help)
+		if f.Short != "" && fs.Lookup(f.Short) == nil {
+			fs.Var(value, f.Short, f.Help)
+		}
+	}
+	return fs
+}
+
+type stringValue string
+
+func (s *stringValue) String() string { return string(*s) }
+
+func (s *stringValue) Set(value string) error {
+	*s = stringValue(value)
+	return nil
+}
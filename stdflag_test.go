@@ -0,0 +1,92 @@
+package cli
+
+import (
+	"flag"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCommand_AddFlagSet(t *testing.T) {
+	fs := flag.NewFlagSet("legacy", flag.ExitOnError)
+	var (
+		name    = fs.String("name", "world", "a `person` to greet")
+		verbose = fs.Bool("verbose", false, "print more")
+		delay   = fs.Duration("delay", time.Second, "wait before greeting")
+	)
+
+	var greeted string
+	a := NewApp("cli")
+	cmd := &Command{
+		Name: "greet",
+		Handle: func(args *Args) int {
+			greeted = *name
+			return 0
+		},
+	}
+	if err := cmd.AddFlagSet(fs); err != nil {
+		t.Fatal(err)
+	}
+	a.Commands = append(a.Commands, cmd)
+
+	var usages []string
+	for _, flag := range cmd.Flags {
+		usages = append(usages, flagUsage(flag, false))
+	}
+	if strings.Join(usages, " ") != "--delay=duration --name=person --verbose" {
+		t.Errorf("unexpected usages: %v", usages)
+	}
+	if cmd.Flags[1].DefValue != "world" || cmd.Flags[1].Help != "a person to greet" {
+		t.Errorf("unexpected flag: %+v", cmd.Flags[1])
+	}
+
+	if exitcode, _ := a.dispatch([]string{"greet", "--verbose", "--name", "gopher", "--delay=1m"}); exitcode != 0 {
+		t.Errorf("finished with code %d", exitcode)
+	}
+	if greeted != "gopher" || !*verbose || *delay != time.Minute {
+		t.Errorf("variables are %q, %v and %v", greeted, *verbose, *delay)
+	}
+
+	fs.Int("memprofilerate", 0, "set the profiling rate")
+	fs.Bool("test.v", false, "be verbose")
+	imported := &Command{Name: "greet"}
+	if err := imported.AddFlagSet(fs); err != nil {
+		t.Errorf("long stdlib flag names are refused: %s", err)
+	}
+	if err := a.AddCommand(imported); err != nil {
+		t.Errorf("command with long stdlib flag names is refused: %s", err)
+	}
+
+	fs.String("bad name", "", "contains a space")
+	if err := (&Command{Name: "greet"}).AddFlagSet(fs); err == nil {
+		t.Error("invalid flag name is accepted")
+	}
+}
+
+func TestCommand_FlagSet(t *testing.T) {
+	var debug toggle
+	cmd := &Command{
+		Name: "greet",
+		Flags: []*Flag{
+			{Name: "name", Short: "n", DefValue: "world", Help: "A person to greet."},
+			{Name: "debug", Value: &debug},
+		},
+	}
+
+	fs := cmd.FlagSet()
+	if f := fs.Lookup("name"); f == nil || f.DefValue != "world" || f.Usage != "A person to greet." {
+		t.Errorf("unexpected flag: %+v", f)
+	}
+	if err := fs.Parse([]string{"-n", "gopher", "-debug", "file.txt"}); err != nil {
+		t.Fatal(err)
+	}
+	if name := fs.Lookup("name").Value.String(); name != "gopher" {
+		t.Errorf("name is %q, expected gopher", name)
+	}
+	if debug != 1 || fs.Arg(0) != "file.txt" {
+		t.Errorf("debug is %d and the argument is %q", debug, fs.Arg(0))
+	}
+	if err := fs.Parse([]string{"--unknown"}); err == nil {
+		t.Error("unknown flag is accepted")
+	}
+}
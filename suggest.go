@@ -0,0 +1,87 @@
+package cli
+
+import (
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// Suggester picks the names a user could've meant by a mistyped one.
+//
+// Suggestions are ranked, the most likely ones go first.
+type Suggester interface {
+	Suggest(typed string, candidates []string) []string
+}
+
+// DistanceSuggester suggests candidates close enough to the typed
+// name, as well as those the typed name is a prefix of.
+//
+// The distance has to be less than the number of runes typed, so
+// single characters don't match one another. Candidates are ranked
+// by their distance, prefix-only matches go last.
+type DistanceSuggester struct {
+	// Distance compares names, DamerauLevenshtein if nil.
+	Distance func(s, t string) float64
+
+	// Threshold is the maximal distance, 2 if zero.
+	Threshold float64
+
+	// MaxResults limits the number of suggestions, no limit if zero.
+	MaxResults int
+
+	// NoPrefix turns prefix matching off.
+	NoPrefix bool
+}
+
+// DefaultSuggester is used by apps that don't have a Suggester.
+var DefaultSuggester Suggester = &DistanceSuggester{}
+
+// Suggest implements Suggester.
+func (s *DistanceSuggester) Suggest(typed string, candidates []string) []string {
+	distance := s.Distance
+	if distance == nil {
+		distance = DamerauLevenshtein
+	}
+	threshold := s.Threshold
+	if threshold == 0 {
+		threshold = 2
+	}
+
+	type ranked struct {
+		name string
+		rank float64
+	}
+
+	var matches []ranked
+	length := float64(utf8.RuneCountInString(typed))
+	for _, candidate := range candidates {
+		d := distance(typed, candidate)
+		switch {
+		case d <= threshold && d < length:
+			matches = append(matches, ranked{candidate, d})
+		case !s.NoPrefix && strings.HasPrefix(strings.ToLower(candidate), strings.ToLower(typed)):
+			matches = append(matches, ranked{candidate, threshold + 1})
+		}
+	}
+
+	sort.SliceStable(matches, func(i, j int) bool {
+		return matches[i].rank < matches[j].rank
+	})
+	if s.MaxResults > 0 && len(matches) > s.MaxResults {
+		matches = matches[:s.MaxResults]
+	}
+
+	suggestions := []string{}
+	for _, match := range matches {
+		suggestions = append(suggestions, match.name)
+	}
+	return suggestions
+}
+
+// suggest picks candidates with the app suggester.
+func (a *App) suggest(typed string, candidates []string) []string {
+	if a.Suggester != nil {
+		return a.Suggester.Suggest(typed, candidates)
+	}
+	return DefaultSuggester.Suggest(typed, candidates)
+}
@@ -0,0 +1,62 @@
+package cli
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDistances(t *testing.T) {
+	check := func(name string, distance func(s, t string) float64, s, u string, expected float64) {
+		if d := distance(s, u); d != expected {
+			t.Errorf("%s(%q, %q) = %v, expected %v", name, s, u, d, expected)
+		}
+	}
+
+	check("Levenshtein", Levenshtein, "", "", 0)
+	check("Levenshtein", Levenshtein, "list", "LIST", 0)
+	check("Levenshtein", Levenshtein, "list", "lsit", 2)
+	check("Levenshtein", Levenshtein, "kitten", "sitting", 3)
+	check("Levenshtein", Levenshtein, "größe", "grösse", 2)
+	check("Levenshtein", Levenshtein, "日本語", "日本", 1)
+
+	check("DamerauLevenshtein", DamerauLevenshtein, "list", "lsit", 1)
+	check("DamerauLevenshtein", DamerauLevenshtein, "ça", "aç", 1)
+
+	check("KeyboardDistance", KeyboardDistance, "list", "lisr", 0.5)
+	check("KeyboardDistance", KeyboardDistance, "list", "lisp", 1)
+	check("KeyboardDistance", KeyboardDistance, "list", "lsit", 1)
+}
+
+func TestDistanceSuggester(t *testing.T) {
+	candidates := []string{"install", "list", "lint", "listen", "status"}
+
+	check := func(s Suggester, typed string, expected []string) {
+		if suggestions := s.Suggest(typed, candidates); !reflect.DeepEqual(suggestions, expected) {
+			t.Errorf("suggestions for %q are %v, expected %v", typed, suggestions, expected)
+		}
+	}
+
+	check(&DistanceSuggester{}, "lsit", []string{"list", "lint"})
+	check(&DistanceSuggester{}, "lis", []string{"list", "lint", "listen"})
+	check(&DistanceSuggester{MaxResults: 1}, "lis", []string{"list"})
+	check(&DistanceSuggester{NoPrefix: true}, "lis", []string{"list", "lint"})
+	check(&DistanceSuggester{Distance: KeyboardDistance, Threshold: 0.5}, "lisr", []string{"list"})
+	check(&DistanceSuggester{}, "x", []string{})
+}
+
+type firstSuggester struct{}
+
+func (firstSuggester) Suggest(typed string, candidates []string) []string {
+	return candidates[:1]
+}
+
+func TestApp_Suggester(t *testing.T) {
+	a := NewApp("cli")
+	a.AddCommand(&Command{Name: "open"})
+	a.AddCommand(&Command{Name: "close"})
+	a.Suggester = firstSuggester{}
+
+	if s := a.SuggestionsFor("whatever"); !reflect.DeepEqual(s, []string{"open"}) {
+		t.Errorf("custom suggester is ignored: %v", s)
+	}
+}
@@ -0,0 +1,48 @@
+package cli
+
+import (
+	"io"
+	"os"
+	"strconv"
+
+	"github.com/ccpaging/cli/internal/term"
+)
+
+// terminalWidth returns the width help gets wrapped to.
+//
+// COLUMNS takes precedence over the actual size of the terminal
+// Stdout is attached to. If neither is known, it falls back to
+// 80 columns.
+func terminalWidth() int {
+	if n, err := strconv.Atoi(os.Getenv("COLUMNS")); err == nil && n > 0 {
+		return n
+	}
+	if f, ok := Stdout.(*os.File); ok {
+		if width, _, err := term.Size(f.Fd()); err == nil && width > 0 {
+			return width
+		}
+	}
+	return term.DefaultWidth
+}
+
+// isTerminal reports whether w is attached to a terminal.
+func isTerminal(w io.Writer) bool {
+	f, ok := w.(*os.File)
+	return ok && term.IsTerminal(f.Fd())
+}
+
+// terminalHeight returns the number of lines the terminal Stdout is
+// attached to can display at once, or zero if it's unknown.
+//
+// LINES takes precedence over the actual size of the terminal.
+func terminalHeight() int {
+	if n, err := strconv.Atoi(os.Getenv("LINES")); err == nil && n > 0 {
+		return n
+	}
+	if f, ok := Stdout.(*os.File); ok {
+		if _, height, err := term.Size(f.Fd()); err == nil {
+			return height
+		}
+	}
+	return 0
+}
@@ -0,0 +1,92 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+// Color modes, accepted by App.Color and the --color global flag.
+const (
+	ColorAuto   = "auto"
+	ColorAlways = "always"
+	ColorNever  = "never"
+)
+
+// Theme describes how help and errors get styled.
+//
+// Each field is a list of SGR parameters, e.g. "1" for bold or "1;31"
+// for bold red. Empty fields leave the text unstyled.
+type Theme struct {
+	Heading string // section headings, e.g. `Usage:`
+	Name    string // command and topic names in lists
+	Flag    string // flag usages in command help
+	Error   string // the `error:` prefix of error messages
+}
+
+// DefaultTheme is used by apps that don't have a Theme of their own.
+var DefaultTheme = &Theme{
+	Heading: "1",
+	Name:    "36",
+	Flag:    "33",
+	Error:   "1;31",
+}
+
+// plainTheme doesn't style anything at all.
+var plainTheme = &Theme{}
+
+// ansiSequence matches SGR escape sequences.
+var ansiSequence = regexp.MustCompile("\x1b\\[[0-9;]*m")
+
+// paint wraps s into the escape sequences of a style.
+func paint(style, s string) string {
+	if style == "" || s == "" {
+		return s
+	}
+	return "\x1b[" + style + "m" + s + "\x1b[0m"
+}
+
+// visibleWidth returns the number of columns s takes on a terminal.
+func visibleWidth(s string) int {
+	if strings.IndexByte(s, '\x1b') >= 0 {
+		s = ansiSequence.ReplaceAllString(s, "")
+	}
+	return utf8.RuneCountInString(s)
+}
+
+// theme returns the theme output to w should be styled with.
+//
+// Styling is on if App.Color is "always". If it's "auto" (or empty),
+// styling is only on when w is a terminal and NO_COLOR is not set.
+func (a *App) theme(w io.Writer) *Theme {
+	switch a.Color {
+	case ColorAlways:
+	case ColorNever:
+		return plainTheme
+	default:
+		if os.Getenv("NO_COLOR") != "" || !isTerminal(w) {
+			return plainTheme
+		}
+	}
+
+	if a.Theme != nil {
+		return a.Theme
+	}
+	return DefaultTheme
+}
+
+// setColor validates and applies the value of the --color flag.
+func (a *App) setColor(when string) error {
+	switch when {
+	case "":
+		a.Color = ColorAlways
+	case ColorAuto, ColorAlways, ColorNever:
+		a.Color = when
+	default:
+		return fmt.Errorf(`invalid --color value %q, expected %s, %s or %s`, when, ColorAuto, ColorAlways, ColorNever)
+	}
+	return nil
+}
@@ -0,0 +1,54 @@
+package cli
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestTheme(t *testing.T) {
+	a := NewApp("cli")
+	a.AddCommand(&Command{Name: "open", Brief: "opens smth"})
+
+	if help := a.globalHelp(); strings.Contains(help, "\x1b[") {
+		t.Errorf("help written to a non-terminal is styled: %q", help)
+	}
+
+	a.Color = ColorAlways
+	help := a.globalHelp()
+	if !strings.Contains(help, "\x1b[1mUsage:\x1b[0m") {
+		t.Errorf("heading isn't bold: %q", help)
+	}
+	if !strings.Contains(help, "\t\x1b[36mopen\x1b[0m        opens smth\n") {
+		t.Errorf("command name isn't colored or aligned: %q", help)
+	}
+
+	a.Theme = &Theme{Name: "35"}
+	if help := a.globalHelp(); !strings.HasPrefix(help, "Usage:\n") || !strings.Contains(help, "\x1b[35mopen") {
+		t.Errorf("custom theme isn't applied: %q", help)
+	}
+}
+
+func TestTheme_Flag(t *testing.T) {
+	a := NewApp("cli")
+	a.AddCommand(&Command{Name: "open", Brief: "opens smth"})
+	defer setArguments()
+	defer output.Reset()
+
+	t.Setenv("NO_COLOR", "1")
+	setArguments("--color", "help")
+	a.Run()
+	if !strings.Contains(output.String(), "\x1b[36mopen") {
+		t.Errorf("--color doesn't override NO_COLOR: %q", output.String())
+	}
+
+	output.Reset()
+	setArguments("--color=never", "help")
+	a.Run()
+	if strings.Contains(output.String(), "\x1b[") {
+		t.Errorf("--color=never is ignored: %q", output.String())
+	}
+
+	if err := a.setColor("sometimes"); err == nil {
+		t.Error("invalid color mode is accepted")
+	}
+}
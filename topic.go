@@ -0,0 +1,130 @@
+package cli
+
+import (
+	"fmt"
+	"io/fs"
+	"path"
+	"strings"
+)
+
+// topicByPath looks a topic up by its path, e.g. ["net", "proxy"]
+// or ["net/proxy"].
+func (a *App) topicByPath(names ...string) *Topic {
+	var parts []string
+	for _, name := range names {
+		parts = append(parts, strings.Split(name, "/")...)
+	}
+
+	topics := a.Topics
+	var found *Topic
+	for _, part := range parts {
+		found = nil
+		for _, topic := range topics {
+			if topic.Name == part {
+				found = topic
+				break
+			}
+		}
+		if found == nil {
+			return nil
+		}
+		topics = found.Topics
+	}
+
+	return found
+}
+
+// walkTopics calls fn for every topic and subtopic with its path.
+func walkTopics(prefix string, topics []*Topic, fn func(path string, topic *Topic)) {
+	for _, topic := range topics {
+		name := topic.Name
+		if prefix != "" {
+			name = prefix + "/" + name
+		}
+		fn(name, topic)
+		walkTopics(name, topic.Topics, fn)
+	}
+}
+
+// Validate checks that every "see also" reference of topics points
+// to an existing command or topic.
+//
+// Run calls it before doing anything else.
+func (a *App) Validate() error {
+	for _, flag := range a.Flags {
+		if err := validateFlag(flag); err != nil {
+			return err
+		}
+	}
+
+	var err error
+	walkTopics("", a.Topics, func(name string, topic *Topic) {
+		for _, ref := range topic.SeeAlso {
+			if err == nil && a.commandByName(ref) == nil && a.topicByPath(ref) == nil {
+				err = fmt.Errorf(`topic %s refers to %q, which is neither a command nor a topic`, name, ref)
+			}
+		}
+	})
+	return err
+}
+
+// LoadTopics makes topics of the markdown files in dir of fsys,
+// which is usually an embed.FS.
+//
+// A file named `name.md` becomes a topic `name`. If the first line
+// of the file is a heading, it's used as the topic brief. Files
+// in the `name` directory become subtopics of the topic.
+func LoadTopics(fsys fs.FS, dir string) ([]*Topic, error) {
+	entries, err := fs.ReadDir(fsys, dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var topics []*Topic
+	byName := make(map[string]*Topic)
+	topicNamed := func(name string) *Topic {
+		if topic, ok := byName[name]; ok {
+			return topic
+		}
+		topic := &Topic{Name: name}
+		byName[name] = topic
+		topics = append(topics, topic)
+		return topic
+	}
+
+	for _, entry := range entries {
+		name := entry.Name()
+		switch {
+		case entry.IsDir():
+			subtopics, err := LoadTopics(fsys, path.Join(dir, name))
+			if err != nil {
+				return nil, err
+			}
+			topicNamed(name).Topics = subtopics
+		case path.Ext(name) == ".md":
+			src, err := fs.ReadFile(fsys, path.Join(dir, name))
+			if err != nil {
+				return nil, err
+			}
+			topic := topicNamed(strings.TrimSuffix(name, ".md"))
+			topic.Brief, topic.Text = parseTopic(string(src))
+		}
+	}
+
+	for _, topic := range topics {
+		if err := validateTopic(topic); err != nil {
+			return nil, fmt.Errorf(`%s: %s`, path.Join(dir, topic.Name), err)
+		}
+	}
+	return topics, nil
+}
+
+// parseTopic splits a markdown topic into its brief and text.
+func parseTopic(src string) (brief, text string) {
+	src = strings.TrimLeft(src, "\r\n")
+	first, rest, _ := strings.Cut(src, "\n")
+	if strings.HasPrefix(first, "# ") {
+		return renderInline(strings.TrimSpace(first[2:])), renderMarkdown(rest)
+	}
+	return "", renderMarkdown(src)
+}
@@ -0,0 +1,106 @@
+package cli
+
+import (
+	"testing"
+	"testing/fstest"
+)
+
+var topicFiles = fstest.MapFS{
+	"docs/net.md": {Data: []byte("# networking basics\n\nHow **networking** works.\n")},
+	"docs/net/proxy.md": {Data: []byte(`# using a proxy
+
+Set the *proxy* with [env](https://example.com/env)
+variables.
+
+## Example
+
+` + "```" + `
+export HTTPS_PROXY=host:3128
+` + "```" + `
+
+- first item
++ second item
+`)},
+	"docs/formats/json.md": {Data: []byte("Plain text.")},
+	"docs/README":          {Data: []byte("not a topic")},
+}
+
+func TestLoadTopics(t *testing.T) {
+	topics, err := LoadTopics(topicFiles, "docs")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	a := NewApp("cli")
+	a.Topics = topics
+
+	if len(a.Topics) != 2 {
+		t.Fatalf("loaded %d topics, expected 2", len(a.Topics))
+	}
+
+	net := a.topicByPath("net")
+	if net == nil || net.Brief != "networking basics" || net.Text != "How networking works." {
+		t.Errorf("unexpected topic: %+v", net)
+	}
+
+	proxy := a.topicByPath("net", "proxy")
+	if proxy != a.topicByPath("net/proxy") || proxy == nil {
+		t.Fatal("subtopic lookup failed")
+	}
+
+	expected := "Set the proxy with env <https://example.com/env>\n" +
+		"variables.\n" +
+		"\n" +
+		"Example\n" +
+		"\n" +
+		"\texport HTTPS_PROXY=host:3128\n" +
+		"\n" +
+		"- first item\n" +
+		"- second item"
+	if proxy.Text != expected {
+		t.Errorf("unexpected topic text:")
+		t.Logf("- expected: %q", expected)
+		t.Logf("- recieved: %q", proxy.Text)
+	}
+
+	if json := a.topicByPath("formats", "json"); json == nil || json.Text != "Plain text." {
+		t.Errorf("unexpected topic: %+v", json)
+	}
+
+	if _, err := LoadTopics(fstest.MapFS{"docs/bad name.md": {}}, "docs"); err == nil {
+		t.Error("topic with an invalid name was loaded")
+	}
+}
+
+func TestTopicHelp(t *testing.T) {
+	topics, err := LoadTopics(topicFiles, "docs")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	a := NewApp("cli")
+	a.Topics = topics
+	net := a.topicByPath("net")
+	net.SeeAlso = []string{"formats/json"}
+
+	if err := a.Validate(); err != nil {
+		t.Error(err)
+	}
+
+	expected := `How networking works.
+
+Subtopics:
+
+	proxy       using a proxy
+
+See also:
+
+	formats/json
+`
+	checkHelp(t, "topic", expected, a.topicHelp(net))
+
+	net.SeeAlso = append(net.SeeAlso, "net/nothing")
+	if err := a.Validate(); err == nil {
+		t.Error("broken reference passed validation")
+	}
+}
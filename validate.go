@@ -0,0 +1,78 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Name limits, as documented on Command, Topic and Flag.
+const (
+	maxNameLength  = 11
+	maxShortLength = 3
+)
+
+func validateCommand(command *Command) error {
+	if command == nil {
+		return fmt.Errorf(`command is nil`)
+	}
+	if err := validateName("command", command.Name, maxNameLength, "_-"); err != nil {
+		return err
+	}
+	if _, err := command.bindings(); err != nil {
+		return fmt.Errorf(`command %q: %s`, command.Name, err)
+	}
+	for _, flag := range command.allFlags() {
+		if err := validateFlag(flag); err != nil {
+			return fmt.Errorf(`command %q: %s`, command.Name, err)
+		}
+	}
+	return nil
+}
+
+func validateTopic(topic *Topic) error {
+	if topic == nil {
+		return fmt.Errorf(`topic is nil`)
+	}
+	return validateName("topic", topic.Name, maxNameLength, "_-")
+}
+
+func validateFlag(flag *Flag) error {
+	if flag == nil {
+		return fmt.Errorf(`flag is nil`)
+	}
+	limit, extra := maxNameLength, "_-"
+	if _, imported := flag.Value.(stdValue); imported {
+		// flags of standard library flag sets keep their names,
+		// e.g. memprofilerate or test.v
+		limit, extra = len(flag.Name), "_-."
+	}
+	if err := validateName("flag", flag.Name, limit, extra); err != nil {
+		return err
+	}
+	if flag.Short == "" {
+		return nil
+	}
+	return validateName("flag "+flag.Name+" short", flag.Short, maxShortLength, "")
+}
+
+// validateName checks that name is a non-empty identifier of up to
+// limit alphanumeric characters or any of the extra ones. Names must
+// start with an alphanumeric character, so they aren't mistaken for
+// flags.
+func validateName(kind, name string, limit int, extra string) error {
+	if name == "" {
+		return fmt.Errorf(`%s name is empty`, kind)
+	}
+	if len(name) > limit {
+		return fmt.Errorf(`%s name %q is longer than %d characters`, kind, name, limit)
+	}
+	for i, r := range name {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+		case i > 0 && strings.ContainsRune(extra, r):
+		default:
+			return fmt.Errorf(`%s name %q contains invalid character %q`, kind, name, r)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,110 @@
+package cli
+
+import (
+	"encoding/json"
+	"runtime/debug"
+)
+
+const versionUsage = "version [--short|--json]"
+
+// readBuildInfo is debug.ReadBuildInfo, replaced in tests.
+var readBuildInfo = debug.ReadBuildInfo
+
+// versionInfo is what the version command reports.
+type versionInfo struct {
+	Name     string `json:"name"`
+	Version  string `json:"version"`
+	Module   string `json:"module,omitempty"`
+	Revision string `json:"revision,omitempty"`
+	Dirty    bool   `json:"dirty,omitempty"`
+	Commit   string `json:"committed,omitempty"`
+	Go       string `json:"go,omitempty"`
+}
+
+// versionInfo returns the version of the application. Version, usually
+// copied from main.version set with -ldflags="-X ...", takes precedence
+// over the version of the main module. Build details are only read if
+// BuildInfo is on.
+func (a *App) versionInfo() versionInfo {
+	info := versionInfo{Name: a.Name, Version: a.Version}
+	if !a.BuildInfo {
+		return info
+	}
+
+	build, ok := readBuildInfo()
+	if !ok {
+		return info
+	}
+	if info.Version == "" && build.Main.Version != "(devel)" {
+		info.Version = build.Main.Version
+	}
+	info.Module = build.Main.Path
+	info.Go = build.GoVersion
+	for _, setting := range build.Settings {
+		switch setting.Key {
+		case "vcs.revision":
+			info.Revision = setting.Value
+		case "vcs.modified":
+			info.Dirty = setting.Value == "true"
+		case "vcs.time":
+			info.Commit = setting.Value
+		}
+	}
+	return info
+}
+
+// version executes the built-in `version` command. Global flags are
+// accepted after it, like after any other command.
+func (a *App) version(arguments []string) (exitCode int, fatal bool) {
+	own := []*Flag{{Name: "short"}, {Name: "json"}}
+	inherited := a.inheritedFlags(own)
+	vars, err := parseVariables(true, append(own, inherited...), arguments)
+	_, short := vars["short"]
+	_, asJSON := vars["json"]
+	if err != nil || short && asJSON {
+		a.printerr("usage: " + a.Name + " " + versionUsage)
+		return 1, true
+	}
+	if err := a.applyGlobals(vars, inherited); err != nil {
+		a.printerr(err)
+		return 1, true
+	}
+
+	info := a.versionInfo()
+	if info.Version == "" {
+		info.Version = "unknown"
+	}
+
+	if short {
+		a.println(info.Version)
+		return 0, false
+	}
+	if asJSON {
+		data, _ := json.MarshalIndent(info, "", "  ")
+		a.println(string(data))
+		return 0, false
+	}
+
+	a.printf("%s version %s\n", a.Name, info.Version)
+	details := definitionList{columned: true}
+	if info.Module != "" {
+		details.items = append(details.items, definition{"module", info.Module})
+	}
+	if info.Revision != "" {
+		revision := info.Revision
+		if info.Dirty {
+			revision += " (modified)"
+		}
+		details.items = append(details.items, definition{"revision", revision})
+	}
+	if info.Commit != "" {
+		details.items = append(details.items, definition{"committed", info.Commit})
+	}
+	if info.Go != "" {
+		details.items = append(details.items, definition{"go", info.Go})
+	}
+	for _, line := range details.lines(terminalWidth()) {
+		a.println(line)
+	}
+	return 0, false
+}
@@ -0,0 +1,65 @@
+package cli
+
+import (
+	"runtime/debug"
+	"testing"
+)
+
+func TestRun_VersionBuildInfo(t *testing.T) {
+	readBuildInfo = func() (*debug.BuildInfo, bool) {
+		return &debug.BuildInfo{
+			GoVersion: "go1.22.1",
+			Main:      debug.Module{Path: "example.com/cli", Version: "v1.2.3"},
+			Settings: []debug.BuildSetting{
+				{Key: "vcs.revision", Value: "0123abcd"},
+				{Key: "vcs.time", Value: "2024-05-01T10:00:00Z"},
+				{Key: "vcs.modified", Value: "true"},
+			},
+		}, true
+	}
+	defer func() { readBuildInfo = debug.ReadBuildInfo }()
+	defer output.Reset()
+
+	a := NewApp("cli")
+	check := func(expected string, arguments ...string) {
+		output.Reset()
+		if exitcode := a.Exec(append([]string{"version"}, arguments...)); exitcode != 0 {
+			t.Errorf("%v finished with code %d", arguments, exitcode)
+		}
+		if output.String() != expected {
+			t.Errorf("unexpected output of %v: %q", arguments, output.String())
+			t.Logf("- expected: %q", expected)
+		}
+	}
+
+	check("cli version unknown\n")
+
+	a.BuildInfo = true
+	check("cli version v1.2.3\n" +
+		"\tmodule      example.com/cli\n" +
+		"\trevision    0123abcd (modified)\n" +
+		"\tcommitted   2024-05-01T10:00:00Z\n" +
+		"\tgo          go1.22.1\n")
+	check("v1.2.3\n", "--short")
+
+	a.Version = "1.3.0-rc1"
+	check("1.3.0-rc1\n", "--short")
+	check("1.3.0-rc1\n", "--short", "--color=never", "--no-pager")
+	if a.Color != ColorNever || a.Pager {
+		t.Errorf("global flags after version aren't applied: color %q, pager %v", a.Color, a.Pager)
+	}
+	check(`{
+  "name": "cli",
+  "version": "1.3.0-rc1",
+  "module": "example.com/cli",
+  "revision": "0123abcd",
+  "dirty": true,
+  "committed": "2024-05-01T10:00:00Z",
+  "go": "go1.22.1"
+}
+`, "--json")
+
+	if exitcode := a.Exec([]string{"version", "--short", "--json"}); exitcode != 1 {
+		t.Errorf("conflicting flags finished with code %d, expected 1", exitcode)
+	}
+}
@@ -0,0 +1,101 @@
+package cli
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// tabWidth is the number of columns a leading tab takes on a terminal.
+const tabWidth = 8
+
+// minTextWidth keeps text readable on extremely narrow terminals.
+const minTextWidth = 20
+
+// reflow wraps text so no line exceeds width columns.
+//
+// Lines of a paragraph are joined and wrapped at word boundaries.
+// Blank lines and lines starting with a space or a tab are kept as
+// they are, so authors can still put down code samples and tables.
+// List items ("- ", "* " or "1. ") start a new line and wrap under
+// their own hanging indent. Words longer than the width are never
+// broken.
+func reflow(text string, width int) []string {
+	if width < minTextWidth {
+		width = minTextWidth
+	}
+
+	var (
+		lines  []string
+		words  []string
+		indent string
+	)
+	flush := func() {
+		if len(words) > 0 {
+			lines = append(lines, fill(words, width, indent)...)
+		}
+		words = nil
+		indent = ""
+	}
+
+	for _, line := range strings.Split(text, "\n") {
+		switch {
+		case strings.TrimSpace(line) == "":
+			flush()
+			lines = append(lines, "")
+		case strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t"):
+			flush()
+			lines = append(lines, line)
+		default:
+			if marker := listMarker(line); marker != "" {
+				flush()
+				indent = strings.Repeat(" ", utf8.RuneCountInString(marker))
+			}
+			words = append(words, strings.Fields(line)...)
+		}
+	}
+	flush()
+
+	return lines
+}
+
+// fill lays out words greedily, continuation lines are prefixed with
+// indent.
+func fill(words []string, width int, indent string) []string {
+	var (
+		lines   []string
+		current strings.Builder
+		length  int
+	)
+	for _, word := range words {
+		n := visibleWidth(word)
+		if length > 0 && length+1+n > width {
+			lines = append(lines, current.String())
+			current.Reset()
+			current.WriteString(indent)
+			length = len(indent)
+		} else if length > 0 {
+			current.WriteString(" ")
+			length++
+		}
+		current.WriteString(word)
+		length += n
+	}
+	return append(lines, current.String())
+}
+
+// listMarker returns the bullet or the number a list item starts with,
+// including the space after it.
+func listMarker(line string) string {
+	for _, bullet := range []string{"- ", "* ", "• "} {
+		if strings.HasPrefix(line, bullet) {
+			return bullet
+		}
+	}
+
+	digits := strings.IndexFunc(line, func(r rune) bool { return !unicode.IsDigit(r) })
+	if digits > 0 && strings.HasPrefix(line[digits:], ". ") {
+		return line[:digits+2]
+	}
+	return ""
+}